@@ -1,10 +1,10 @@
 package commands
 
 import (
-	"encoding/json"
 	"fmt"
+	"time"
 
-	"github.com/Gahroot/agentHQ-cli/internal/common/client"
+	"github.com/Gahroot/agentHQ-cli/pkg/agenthq"
 	"github.com/Gahroot/agentHQ-cli/pkg/output"
 	"github.com/spf13/cobra"
 )
@@ -28,26 +28,18 @@ func newActivityLogCmd() *cobra.Command {
 		Use:   "log",
 		Short: "Log an activity",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, err := client.New()
+			c, err := agenthq.New()
 			if err != nil {
-				output.PrintError(err.Error())
-				return nil
+				return err
 			}
 
-			body := map[string]string{
-				"action": action,
-			}
-			if resourceType != "" {
-				body["resource_type"] = resourceType
-			}
-			if resourceID != "" {
-				body["resource_id"] = resourceID
-			}
-
-			_, err = c.Post("/api/v1/activity", body)
+			_, err = c.Activity.Log(cmd.Context(), agenthq.ActivityLogParams{
+				Action:       action,
+				ResourceType: resourceType,
+				ResourceID:   resourceID,
+			})
 			if err != nil {
-				output.PrintError(fmt.Sprintf("Failed to log activity: %v", err))
-				return nil
+				return fmt.Errorf("Failed to log activity: %w", err)
 			}
 
 			output.PrintSuccess("Activity logged")
@@ -65,58 +57,45 @@ func newActivityLogCmd() *cobra.Command {
 
 func newActivityListCmd() *cobra.Command {
 	var actorID, action string
+	var pages pageFlags
 
 	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List activity log entries",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, err := client.New()
+			c, err := agenthq.New()
 			if err != nil {
-				output.PrintError(err.Error())
-				return nil
+				return err
 			}
 
-			query := map[string]string{}
-			if actorID != "" {
-				query["actor_id"] = actorID
-			}
-			if action != "" {
-				query["action"] = action
-			}
-
-			resp, err := c.Get("/api/v1/activity", query)
+			entries, pagination, raw, err := fetchPages(cmd.Context(), pages, c.Activity.Iter(agenthq.ActivityListParams{
+				ListOptions: pages.options(),
+				ActorID:     actorID,
+				Action:      action,
+			}))
 			if err != nil {
-				output.PrintError(fmt.Sprintf("Failed to list activity: %v", err))
-				return nil
+				return fmt.Errorf("Failed to list activity: %w", err)
 			}
 
-			if output.JSONMode {
-				output.PrintJSON(json.RawMessage(resp.Data))
-				return nil
-			}
-
-			var entries []struct {
-				ID        string `json:"id"`
-				ActorID   string `json:"actor_id"`
-				Action    string `json:"action"`
-				CreatedAt string `json:"created_at"`
-			}
-			if err := json.Unmarshal(resp.Data, &entries); err != nil {
-				output.PrintError(fmt.Sprintf("Failed to parse response: %v", err))
-				return nil
+			if output.Structured() {
+				return printRaw(raw, entries)
 			}
 
 			rows := make([][]string, len(entries))
 			for i, e := range entries {
-				rows[i] = []string{e.ID, e.ActorID, e.Action, e.CreatedAt}
+				rows[i] = []string{e.ID, e.ActorID, e.Action, output.Time(e.CreatedAt, time.RFC3339)}
+			}
+			if err := output.PrintTable([]string{"ID", "ACTOR", "ACTION", "TIME"}, rows); err != nil {
+				return err
 			}
-			output.PrintTable([]string{"ID", "ACTOR", "ACTION", "TIME"}, rows)
+			printMoreHint(len(entries), pagination)
 			return nil
 		},
 	}
 
 	cmd.Flags().StringVar(&actorID, "actor", "", "Filter by actor ID")
 	cmd.Flags().StringVar(&action, "action", "", "Filter by action")
+	pages.register(cmd)
 
 	return cmd
 }
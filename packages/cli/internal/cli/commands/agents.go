@@ -1,10 +1,10 @@
 package commands
 
 import (
-	"encoding/json"
 	"fmt"
+	"time"
 
-	"github.com/Gahroot/agentHQ-cli/internal/common/client"
+	"github.com/Gahroot/agentHQ-cli/pkg/agenthq"
 	"github.com/Gahroot/agentHQ-cli/pkg/output"
 	"github.com/spf13/cobra"
 )
@@ -22,44 +22,40 @@ func NewAgentCmd() *cobra.Command {
 }
 
 func newAgentListCmd() *cobra.Command {
-	return &cobra.Command{
+	var pages pageFlags
+
+	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List agents in organization",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, err := client.New()
+			c, err := agenthq.New()
 			if err != nil {
-				output.PrintError(err.Error())
-				return nil
+				return err
 			}
-			resp, err := c.Get("/api/v1/agents", nil)
+			agents, pagination, raw, err := fetchPages(cmd.Context(), pages, c.Agents.Iter(pages.options()))
 			if err != nil {
-				output.PrintError(fmt.Sprintf("Failed to list agents: %v", err))
-				return nil
+				return fmt.Errorf("Failed to list agents: %w", err)
 			}
 
-			if output.JSONMode {
-				output.PrintJSON(json.RawMessage(resp.Data))
-				return nil
-			}
-
-			var agents []struct {
-				ID     string `json:"id"`
-				Name   string `json:"name"`
-				Status string `json:"status"`
-			}
-			if err := json.Unmarshal(resp.Data, &agents); err != nil {
-				output.PrintError(fmt.Sprintf("Failed to parse response: %v", err))
-				return nil
+			if output.Structured() {
+				return printRaw(raw, agents)
 			}
 
 			rows := make([][]string, len(agents))
 			for i, a := range agents {
-				rows[i] = []string{a.ID, a.Name, a.Status}
+				rows[i] = []string{a.ID, a.Name, output.Badge(a.Status)}
+			}
+			if err := output.PrintTable([]string{"ID", "NAME", "STATUS"}, rows); err != nil {
+				return err
 			}
-			output.PrintTable([]string{"ID", "NAME", "STATUS"}, rows)
+			printMoreHint(len(agents), pagination)
 			return nil
 		},
 	}
+
+	pages.register(cmd)
+
+	return cmd
 }
 
 func newAgentStatusCmd() *cobra.Command {
@@ -67,42 +63,29 @@ func newAgentStatusCmd() *cobra.Command {
 		Use:   "status",
 		Short: "Show agent online/offline status",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, err := client.New()
+			c, err := agenthq.New()
 			if err != nil {
-				output.PrintError(err.Error())
-				return nil
+				return err
 			}
-			resp, err := c.Get("/api/v1/agents", nil)
+			it := c.Agents.Iter(agenthq.ListOptions{Limit: allPagesLimit})
+			agents, _, err := it.All(cmd.Context())
 			if err != nil {
-				output.PrintError(fmt.Sprintf("Failed to get agent status: %v", err))
-				return nil
+				return fmt.Errorf("Failed to get agent status: %w", err)
 			}
 
-			if output.JSONMode {
-				output.PrintJSON(json.RawMessage(resp.Data))
-				return nil
-			}
-
-			var agents []struct {
-				Name          string `json:"name"`
-				Status        string `json:"status"`
-				LastHeartbeat string `json:"last_heartbeat"`
-			}
-			if err := json.Unmarshal(resp.Data, &agents); err != nil {
-				output.PrintError(fmt.Sprintf("Failed to parse response: %v", err))
-				return nil
+			if output.Structured() {
+				return printRaw(it.Raw(), agents)
 			}
 
 			rows := make([][]string, len(agents))
 			for i, a := range agents {
-				hb := a.LastHeartbeat
-				if hb == "" {
-					hb = "never"
+				hb := "never"
+				if a.LastHeartbeat != nil {
+					hb = output.Time(*a.LastHeartbeat, time.RFC3339)
 				}
-				rows[i] = []string{a.Name, a.Status, hb}
+				rows[i] = []string{a.Name, output.Badge(a.Status), hb}
 			}
-			output.PrintTable([]string{"NAME", "STATUS", "LAST HEARTBEAT"}, rows)
-			return nil
+			return output.PrintTable([]string{"NAME", "STATUS", "LAST HEARTBEAT"}, rows)
 		},
 	}
 }
@@ -7,6 +7,7 @@ import (
 
 	"github.com/Gahroot/agentHQ-cli/internal/common/client"
 	"github.com/Gahroot/agentHQ-cli/internal/common/config"
+	"github.com/Gahroot/agentHQ-cli/pkg/agenthq"
 	"github.com/Gahroot/agentHQ-cli/pkg/output"
 	"github.com/spf13/cobra"
 )
@@ -27,48 +28,27 @@ func NewAuthCmd() *cobra.Command {
 }
 
 func newLoginCmd() *cobra.Command {
-	var email, password, hubURL string
+	var email, password string
 
 	cmd := &cobra.Command{
 		Use:   "login",
 		Short: "Login as a human user",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if hubURL == "" {
-				hubURL = "http://localhost:3000"
-			}
-			c := client.NewWithToken(hubURL, "")
-			resp, err := c.Post("/api/v1/auth/login", map[string]string{
-				"email":    email,
-				"password": password,
-			})
+			hubURL := profileHubURL()
+			c := agenthq.NewWithToken(hubURL, "")
+			data, err := c.Auth.Login(cmd.Context(), email, password)
 			if err != nil {
-				output.PrintError(fmt.Sprintf("Login failed: %v", err))
-				return nil
-			}
-
-			var data struct {
-				User struct {
-					ID    string `json:"id"`
-					Email string `json:"email"`
-					Name  string `json:"name"`
-					OrgID string `json:"org_id"`
-				} `json:"user"`
-				AccessToken  string `json:"accessToken"`
-				RefreshToken string `json:"refreshToken"`
-			}
-			if err := json.Unmarshal(resp.Data, &data); err != nil {
-				output.PrintError(fmt.Sprintf("Failed to parse response: %v", err))
-				return nil
+				return fmt.Errorf("Login failed: %w", err)
 			}
 
 			cfg := &config.Config{
-				HubURL:   hubURL,
-				JWTToken: data.AccessToken,
-				OrgID:    data.User.OrgID,
+				HubURL:       hubURL,
+				JWTToken:     data.AccessToken,
+				RefreshToken: data.RefreshToken,
+				OrgID:        data.User.OrgID,
 			}
 			if err := config.Save(cfg); err != nil {
-				output.PrintError(fmt.Sprintf("Failed to save config: %v", err))
-				return nil
+				return fmt.Errorf("Failed to save config: %w", err)
 			}
 
 			output.PrintSuccess(fmt.Sprintf("Logged in as %s (%s)", data.User.Name, data.User.Email))
@@ -78,7 +58,6 @@ func newLoginCmd() *cobra.Command {
 
 	cmd.Flags().StringVar(&email, "email", "", "Email address")
 	cmd.Flags().StringVar(&password, "password", "", "Password")
-	cmd.Flags().StringVar(&hubURL, "hub-url", "", "Hub URL (default: http://localhost:3000)")
 	cmd.MarkFlagRequired("email")
 	cmd.MarkFlagRequired("password")
 
@@ -86,35 +65,17 @@ func newLoginCmd() *cobra.Command {
 }
 
 func newLoginAgentCmd() *cobra.Command {
-	var name, description, hubURL, token string
+	var name, description, token string
 
 	cmd := &cobra.Command{
 		Use:   "login-agent",
 		Short: "Register this machine as an agent",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if hubURL == "" {
-				hubURL = "http://localhost:3000"
-			}
-			c := client.NewWithToken(hubURL, token)
-			resp, err := c.Post("/api/v1/auth/agents/register", map[string]string{
-				"name":        name,
-				"description": description,
-			})
+			hubURL := profileHubURL()
+			c := agenthq.NewWithToken(hubURL, token)
+			data, err := c.Auth.RegisterAgent(cmd.Context(), name, description)
 			if err != nil {
-				output.PrintError(fmt.Sprintf("Agent registration failed: %v", err))
-				return nil
-			}
-
-			var data struct {
-				Agent struct {
-					ID    string `json:"id"`
-					OrgID string `json:"org_id"`
-				} `json:"agent"`
-				APIKey string `json:"apiKey"`
-			}
-			if err := json.Unmarshal(resp.Data, &data); err != nil {
-				output.PrintError(fmt.Sprintf("Failed to parse response: %v", err))
-				return nil
+				return fmt.Errorf("Agent registration failed: %w", err)
 			}
 
 			cfg := &config.Config{
@@ -124,8 +85,7 @@ func newLoginAgentCmd() *cobra.Command {
 				AgentID: data.Agent.ID,
 			}
 			if err := config.Save(cfg); err != nil {
-				output.PrintError(fmt.Sprintf("Failed to save config: %v", err))
-				return nil
+				return fmt.Errorf("Failed to save config: %w", err)
 			}
 
 			output.PrintSuccess(fmt.Sprintf("Agent registered: %s (ID: %s)", name, data.Agent.ID))
@@ -136,7 +96,6 @@ func newLoginAgentCmd() *cobra.Command {
 
 	cmd.Flags().StringVar(&name, "name", "", "Agent name")
 	cmd.Flags().StringVar(&description, "description", "", "Agent description")
-	cmd.Flags().StringVar(&hubURL, "hub-url", "", "Hub URL")
 	cmd.Flags().StringVar(&token, "token", "", "JWT token for auth")
 	cmd.MarkFlagRequired("name")
 	cmd.MarkFlagRequired("token")
@@ -144,6 +103,19 @@ func newLoginAgentCmd() *cobra.Command {
 	return cmd
 }
 
+// profileHubURL is the hub to log in to: the global --hub-url, else the
+// active profile's after environment overrides.
+func profileHubURL() string {
+	cfg, err := config.Load()
+	if err != nil {
+		if config.HubURLOverride != "" {
+			return config.HubURLOverride
+		}
+		return "http://localhost:3000"
+	}
+	return cfg.HubURL
+}
+
 func newWhoamiCmd() *cobra.Command {
 	return &cobra.Command{
 		Use:   "whoami",
@@ -151,8 +123,10 @@ func newWhoamiCmd() *cobra.Command {
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cfg, err := config.Load()
 			if err != nil {
-				output.PrintError("Not logged in")
-				return nil
+				return &client.AuthError{Message: "Not logged in"}
+			}
+			if err := cfg.LoadCredentials(); err != nil {
+				return err
 			}
 
 			if cfg.APIKey != "" {
@@ -160,7 +134,7 @@ func newWhoamiCmd() *cobra.Command {
 			} else if cfg.JWTToken != "" {
 				output.PrintSuccess(fmt.Sprintf("User, Org: %s, Hub: %s", cfg.OrgID, cfg.HubURL))
 			} else {
-				output.PrintError("Not logged in")
+				return &client.AuthError{Message: "Not logged in"}
 			}
 			return nil
 		},
@@ -172,10 +146,18 @@ func newLogoutCmd() *cobra.Command {
 		Use:   "logout",
 		Short: "Clear stored credentials",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			cfg := &config.Config{HubURL: "http://localhost:3000"}
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("Failed to load config: %w", err)
+			}
+			if err := cfg.LoadCredentials(); err != nil {
+				return err
+			}
+			// Keep the profile's hub so logging back in only needs credentials.
+			cfg.APIKey, cfg.JWTToken, cfg.RefreshToken = "", "", ""
+			cfg.OrgID, cfg.AgentID = "", ""
 			if err := config.Save(cfg); err != nil {
-				output.PrintError(fmt.Sprintf("Failed to clear config: %v", err))
-				return nil
+				return fmt.Errorf("Failed to clear config: %w", err)
 			}
 			output.PrintSuccess("Logged out")
 			return nil
@@ -191,13 +173,14 @@ func newExportCmd() *cobra.Command {
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cfg, err := config.Load()
 			if err != nil {
-				output.PrintError("Not logged in")
-				return nil
+				return &client.AuthError{Message: "Not logged in"}
+			}
+			if err := cfg.LoadCredentials(); err != nil {
+				return err
 			}
 
 			if cfg.APIKey == "" {
-				output.PrintError("No agent credentials found. Please run 'agenthq auth login-agent' first.")
-				return nil
+				return &client.AuthError{Message: "No agent credentials found. Please run 'agenthq auth login-agent' first."}
 			}
 
 			// Output in a clean format for pocket-agent to consume
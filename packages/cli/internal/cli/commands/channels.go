@@ -1,10 +1,9 @@
 package commands
 
 import (
-	"encoding/json"
 	"fmt"
 
-	"github.com/Gahroot/agentHQ-cli/internal/common/client"
+	"github.com/Gahroot/agentHQ-cli/pkg/agenthq"
 	"github.com/Gahroot/agentHQ-cli/pkg/output"
 	"github.com/spf13/cobra"
 )
@@ -26,39 +25,26 @@ func newChannelListCmd() *cobra.Command {
 		Use:   "list",
 		Short: "List channels",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, err := client.New()
+			c, err := agenthq.New()
 			if err != nil {
-				output.PrintError(err.Error())
-				return nil
+				return err
 			}
 
-			resp, err := c.Get("/api/v1/channels", nil)
+			var raw agenthq.RawData
+			channels, err := c.WithRaw(&raw).Channels.List(cmd.Context())
 			if err != nil {
-				output.PrintError(fmt.Sprintf("Failed to list channels: %v", err))
-				return nil
+				return fmt.Errorf("Failed to list channels: %w", err)
 			}
 
-			if output.JSONMode {
-				output.PrintJSON(json.RawMessage(resp.Data))
-				return nil
-			}
-
-			var channels []struct {
-				ID   string `json:"id"`
-				Name string `json:"name"`
-				Type string `json:"type"`
-			}
-			if err := json.Unmarshal(resp.Data, &channels); err != nil {
-				output.PrintError(fmt.Sprintf("Failed to parse response: %v", err))
-				return nil
+			if output.Structured() {
+				return printRaw(raw.JSON(), channels)
 			}
 
 			rows := make([][]string, len(channels))
 			for i, ch := range channels {
 				rows[i] = []string{ch.ID, ch.Name, ch.Type}
 			}
-			output.PrintTable([]string{"ID", "NAME", "TYPE"}, rows)
-			return nil
+			return output.PrintTable([]string{"ID", "NAME", "TYPE"}, rows)
 		},
 	}
 }
@@ -71,38 +57,24 @@ func newChannelCreateCmd() *cobra.Command {
 		Short: "Create a channel",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, err := client.New()
+			c, err := agenthq.New()
 			if err != nil {
-				output.PrintError(err.Error())
-				return nil
+				return err
 			}
 
-			body := map[string]string{
-				"name": args[0],
-			}
-			if description != "" {
-				body["description"] = description
-			}
-
-			resp, err := c.Post("/api/v1/channels", body)
+			var raw agenthq.RawData
+			ch, err := c.WithRaw(&raw).Channels.Create(cmd.Context(), agenthq.ChannelCreateParams{
+				Name:        args[0],
+				Description: description,
+			})
 			if err != nil {
-				output.PrintError(fmt.Sprintf("Failed to create channel: %v", err))
-				return nil
+				return fmt.Errorf("Failed to create channel: %w", err)
 			}
 
-			if output.JSONMode {
-				output.PrintJSON(json.RawMessage(resp.Data))
-				return nil
+			if output.Structured() {
+				return printRaw(raw.JSON(), ch)
 			}
 
-			var ch struct {
-				ID   string `json:"id"`
-				Name string `json:"name"`
-			}
-			if err := json.Unmarshal(resp.Data, &ch); err != nil {
-				output.PrintError(fmt.Sprintf("Failed to parse response: %v", err))
-				return nil
-			}
 			output.PrintSuccess(fmt.Sprintf("Channel created: %s (%s)", ch.Name, ch.ID))
 			return nil
 		},
@@ -0,0 +1,118 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/Gahroot/agentHQ-cli/internal/common/client"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// scissors separates what the user writes from the template below it, as
+// in git commit --verbose. Lines starting with # can't be comments because
+// Markdown headings start with #.
+const scissors = "# ------------------------ >8 ------------------------"
+
+// stdinIsTerminal is replaced in tests.
+var stdinIsTerminal = func() bool { return term.IsTerminal(int(os.Stdin.Fd())) }
+
+// contentFlags holds --content and --content-file for commands that take
+// Markdown content.
+type contentFlags struct {
+	content string
+	file    string
+}
+
+func (f *contentFlags) register(cmd *cobra.Command, usage string) {
+	cmd.Flags().StringVar(&f.content, "content", "", usage+" (- reads stdin; omit to write it in $EDITOR)")
+	cmd.Flags().StringVar(&f.file, "content-file", "", "Read the content from a file (- for stdin)")
+}
+
+// given reports whether --content or --content-file was used.
+func (f *contentFlags) given(cmd *cobra.Command) bool {
+	return cmd.Flags().Changed("content") || cmd.Flags().Changed("content-file")
+}
+
+// value returns the content from --content, --content-file or stdin.
+func (f *contentFlags) value(cmd *cobra.Command) (string, error) {
+	if cmd.Flags().Changed("content") && cmd.Flags().Changed("content-file") {
+		return "", &client.ValidationError{Message: "--content and --content-file cannot be used together"}
+	}
+
+	var data []byte
+	var err error
+	switch {
+	case f.content == "-" || f.file == "-":
+		data, err = io.ReadAll(cmd.InOrStdin())
+	case f.file != "":
+		data, err = os.ReadFile(f.file)
+	default:
+		return f.content, nil
+	}
+	if err != nil {
+		return "", &client.ValidationError{Message: fmt.Sprintf("Failed to read content: %v", err)}
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// get returns the content from the flags or, if neither was given and stdin
+// is a terminal, from the editor. Without either it fails like a missing
+// required flag.
+func (f *contentFlags) get(cmd *cobra.Command, info ...string) (string, error) {
+	if f.given(cmd) {
+		return f.value(cmd)
+	}
+	if !stdinIsTerminal() {
+		return "", &client.ValidationError{Message: "--content or --content-file is required"}
+	}
+	return compose(cmd.Context(), "", info...)
+}
+
+// compose opens the editor on initial followed by a template of info lines,
+// and returns what was written above the template. Empty content aborts.
+func compose(ctx context.Context, initial string, info ...string) (string, error) {
+	tmp, err := os.CreateTemp("", "agenthq-*.md")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+
+	var b strings.Builder
+	b.WriteString(initial)
+	b.WriteString("\n\n" + scissors + "\n")
+	b.WriteString("# Write the content above this line in Markdown. Everything below it is\n")
+	b.WriteString("# ignored, and saving empty content aborts.\n")
+	if len(info) > 0 {
+		b.WriteString("#\n")
+	}
+	for _, line := range info {
+		b.WriteString("# " + line + "\n")
+	}
+	if _, err := tmp.WriteString(b.String()); err != nil {
+		tmp.Close()
+		return "", err
+	}
+	tmp.Close()
+
+	if err := runEditor(ctx, tmp.Name()); err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(tmp.Name())
+	if err != nil {
+		return "", err
+	}
+
+	content := string(data)
+	if i := strings.Index(content, scissors); i >= 0 {
+		content = content[:i]
+	}
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return "", &client.ValidationError{Message: "Aborting: empty content"}
+	}
+	return content, nil
+}
@@ -0,0 +1,93 @@
+package commands
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func newContentCmd(body *contentFlags, stdin string) *cobra.Command {
+	cmd := &cobra.Command{Use: "test"}
+	body.register(cmd, "Content")
+	cmd.SetIn(strings.NewReader(stdin))
+	return cmd
+}
+
+func TestContentFlags(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "post.md")
+	os.WriteFile(file, []byte("# From file\n\nBody\n"), 0600)
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    string
+		wantErr bool
+	}{
+		{"inline", []string{"--content", "hello"}, "hello", false},
+		{"stdin", []string{"--content", "-"}, "from stdin", false},
+		{"file", []string{"--content-file", file}, "# From file\n\nBody", false},
+		{"file stdin", []string{"--content-file", "-"}, "from stdin", false},
+		{"missing file", []string{"--content-file", file + ".missing"}, "", true},
+		{"both", []string{"--content", "x", "--content-file", file}, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body contentFlags
+			cmd := newContentCmd(&body, "from stdin\n")
+			cmd.ParseFlags(tt.args)
+			got, err := body.get(cmd)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("get() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("get() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestContentFlags_Required(t *testing.T) {
+	old := stdinIsTerminal
+	stdinIsTerminal = func() bool { return false }
+	defer func() { stdinIsTerminal = old }()
+
+	var body contentFlags
+	if _, err := body.get(newContentCmd(&body, "")); err == nil {
+		t.Error("expected error without content when stdin is not a terminal")
+	}
+}
+
+func TestCompose(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("editor script needs sh")
+	}
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("VISUAL", "")
+
+	// The editor keeps the template, replacing the initial text.
+	editor := filepath.Join(t.TempDir(), "editor")
+	os.WriteFile(editor, []byte(`#!/bin/sh
+grep -q "Channel: general" "$1" || exit 1
+{ printf '## Status\n\nAll green\n'; grep -A100 -- '>8' "$1"; } > "$1.new" && mv "$1.new" "$1"
+`), 0700)
+	t.Setenv("EDITOR", editor)
+
+	got, err := compose(context.Background(), "draft", "Channel: general")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != "## Status\n\nAll green" {
+		t.Errorf("expected content above the scissors line, got %q", got)
+	}
+
+	t.Setenv("EDITOR", "true")
+	if _, err := compose(context.Background(), "", "Channel: general"); err == nil || !strings.Contains(err.Error(), "empty") {
+		t.Errorf("expected empty content to abort, got %v", err)
+	}
+}
@@ -1,13 +1,20 @@
 package commands
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/Gahroot/agentHQ-cli/internal/common/client"
 	"github.com/Gahroot/agentHQ-cli/internal/common/config"
+	"github.com/Gahroot/agentHQ-cli/pkg/agenthq"
 	"github.com/Gahroot/agentHQ-cli/pkg/output"
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
 )
 
 func NewConfigCmd() *cobra.Command {
@@ -18,77 +25,434 @@ func NewConfigCmd() *cobra.Command {
 
 	cmd.AddCommand(newConfigSetCmd())
 	cmd.AddCommand(newConfigGetCmd())
+	cmd.AddCommand(newConfigUnsetCmd())
+	cmd.AddCommand(newConfigListCmd())
+	cmd.AddCommand(newConfigValidateCmd())
+	cmd.AddCommand(newConfigEditCmd())
+	cmd.AddCommand(newConfigMigrateCredentialsCmd())
 
 	return cmd
 }
 
+// lookupSettableKey returns the registered key called name, or a usage error
+// if it is unknown or changed by another command.
+func lookupSettableKey(name string) (*config.Key, error) {
+	k, ok := config.LookupKey(name)
+	if !ok {
+		return nil, &client.ValidationError{Message: fmt.Sprintf("Unknown config key: %s (see 'agenthq config list')", name)}
+	}
+	if k.ManagedBy != "" {
+		return nil, &client.ValidationError{Message: fmt.Sprintf("%s is changed with '%s'", name, k.ManagedBy)}
+	}
+	return k, nil
+}
+
+// setConfigValue stores val for k in the active profile or the preferences,
+// returning where it went.
+func setConfigValue(k *config.Key, val string) (string, error) {
+	if k.Scope == config.ScopeProfile {
+		cfg, err := config.Load()
+		if err != nil {
+			return "", fmt.Errorf("Failed to load config: %w", err)
+		}
+		if k.Secret {
+			if err := cfg.LoadCredentials(); err != nil {
+				return "", err
+			}
+		}
+		cfg.SetValue(k.Name, val)
+		if err := config.Save(cfg); err != nil {
+			return "", fmt.Errorf("Failed to save config: %w", err)
+		}
+		return "profile " + cfg.Profile, nil
+	}
+
+	f, err := config.LoadFile()
+	if err != nil {
+		return "", fmt.Errorf("Failed to load config: %w", err)
+	}
+	f.SetPreference(k.Name, val)
+	if err := config.SaveFile(f); err != nil {
+		return "", fmt.Errorf("Failed to save config: %w", err)
+	}
+	return "preferences", nil
+}
+
 func newConfigSetCmd() *cobra.Command {
 	return &cobra.Command{
 		Use:   "set <key> <value>",
 		Short: "Set a config value",
+		Long:  "Set a config value in the active profile or, for preferences, globally. Pass - as the value to read it from stdin, keeping secrets out of shell history. See 'agenthq config list' for keys.",
 		Args:  cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			cfg, err := config.Load()
+			k, err := lookupSettableKey(args[0])
 			if err != nil {
-				output.PrintError(fmt.Sprintf("Failed to load config: %v", err))
-				return nil
+				return err
 			}
 
-			key, val := args[0], args[1]
-			switch key {
-			case "hub_url":
-				cfg.HubURL = val
-			case "api_key":
-				cfg.APIKey = val
-			case "org_id":
-				cfg.OrgID = val
-			case "agent_id":
-				cfg.AgentID = val
-			default:
-				output.PrintError(fmt.Sprintf("Unknown config key: %s", key))
-				return nil
+			val := args[1]
+			if val == "-" {
+				data, err := io.ReadAll(cmd.InOrStdin())
+				if err != nil {
+					return fmt.Errorf("Failed to read value: %w", err)
+				}
+				val = strings.TrimSpace(string(data))
+			}
+			if err := k.Validate(val); err != nil {
+				return &client.ValidationError{Message: err.Error()}
 			}
 
-			if err := config.Save(cfg); err != nil {
-				output.PrintError(fmt.Sprintf("Failed to save config: %v", err))
-				return nil
+			where, err := setConfigValue(k, val)
+			if err != nil {
+				return err
+			}
+			output.PrintSuccess(fmt.Sprintf("Config %s set to %s (%s)", k.Name, k.Mask(val), where))
+			return nil
+		},
+	}
+}
+
+func newConfigUnsetCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "unset <key>",
+		Short: "Remove a config value, restoring its default",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			k, err := lookupSettableKey(args[0])
+			if err != nil {
+				return err
 			}
 
-			output.PrintSuccess(fmt.Sprintf("Config %s set to %s", key, val))
+			where, err := setConfigValue(k, "")
+			if err != nil {
+				return err
+			}
+			output.PrintSuccess(fmt.Sprintf("Config %s unset (%s)", k.Name, where))
 			return nil
 		},
 	}
 }
 
+// configValue is a key's effective value and where it came from.
+type configValue struct {
+	Key    string `json:"key"`
+	Value  string `json:"value"`
+	Source string `json:"source"`
+}
+
+// configValues returns the effective value of every key that has one, with
+// secrets masked unless reveal is set.
+func configValues(reveal bool) (*config.Config, *config.File, []configValue, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("Failed to load config: %w", err)
+	}
+	if err := cfg.LoadCredentials(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: credentials not shown: %v\n", err)
+	}
+	f, err := config.LoadFile()
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("Failed to load config: %w", err)
+	}
+
+	var values []configValue
+	for _, k := range config.Keys {
+		var val, source string
+		if k.Scope == config.ScopeProfile {
+			val, source = cfg.Value(k.Name), cfg.Sources[k.Name]
+		} else if v, ok := f.Value(k.Name); ok {
+			val, source = v, "config"
+		} else {
+			val, source = k.Default, "default"
+			if k.Name == "credential_store" {
+				val = f.StoreName()
+			}
+		}
+		if val == "" {
+			continue
+		}
+		if !reveal {
+			val = k.Mask(val)
+		}
+		values = append(values, configValue{Key: k.Name, Value: val, Source: source})
+	}
+	return cfg, f, values, nil
+}
+
 func newConfigGetCmd() *cobra.Command {
+	var reveal bool
+
+	cmd := &cobra.Command{
+		Use:   "get [key]",
+		Short: "Show configuration and where each value comes from",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 1 {
+				if _, ok := config.LookupKey(args[0]); !ok {
+					return &client.ValidationError{Message: fmt.Sprintf("Unknown config key: %s (see 'agenthq config list')", args[0])}
+				}
+			}
+
+			cfg, f, values, err := configValues(reveal)
+			if err != nil {
+				return err
+			}
+
+			if len(args) == 1 {
+				v := configValue{Key: args[0]}
+				for _, value := range values {
+					if value.Key == args[0] {
+						v = value
+					}
+				}
+				if output.Structured() {
+					return output.Print(v)
+				}
+				fmt.Println(v.Value)
+				return nil
+			}
+
+			project, _ := config.LoadProject()
+			if output.Structured() {
+				display := map[string]interface{}{"profile": cfg.Profile, "values": values}
+				if project != nil && project.Path != "" {
+					display["project"] = project.Path
+				}
+				return output.Print(display)
+			}
+
+			fmt.Printf("Profile: %s\n", cfg.Profile)
+			if project != nil && project.Path != "" {
+				fmt.Printf("Project config: %s\n", project.Path)
+			}
+			if f.HasPlaintextCredentials() {
+				fmt.Println("  Credentials from an older version are stored unencrypted. They move to the credential store on the next login, or run 'agenthq config migrate-credentials'.")
+			}
+			rows := make([][]string, len(values))
+			for i, v := range values {
+				rows[i] = []string{v.Key, v.Value, v.Source}
+			}
+			return output.PrintTable([]string{"KEY", "VALUE", "SOURCE"}, rows)
+		},
+	}
+
+	cmd.Flags().BoolVar(&reveal, "show-secrets", false, "Show API keys and tokens unmasked")
+
+	return cmd
+}
+
+func newConfigListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List config keys",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			type keyInfo struct {
+				Key         string   `json:"key"`
+				Type        string   `json:"type"`
+				Scope       string   `json:"scope"`
+				Default     string   `json:"default,omitempty"`
+				Values      []string `json:"values,omitempty"`
+				Secret      bool     `json:"secret"`
+				Description string   `json:"description"`
+			}
+
+			keys := make([]keyInfo, len(config.Keys))
+			for i, k := range config.Keys {
+				keys[i] = keyInfo{k.Name, k.Type, k.Scope, k.Default, k.Values, k.Secret, k.Description}
+			}
+
+			if output.Structured() {
+				return output.Print(keys)
+			}
+
+			rows := make([][]string, len(keys))
+			for i, k := range keys {
+				typ := k.Type
+				if len(k.Values) > 0 {
+					typ = strings.Join(k.Values, "|")
+				}
+				rows[i] = []string{k.Key, typ, k.Scope, k.Default, k.Description}
+			}
+			return output.PrintTable([]string{"KEY", "TYPE", "SCOPE", "DEFAULT", "DESCRIPTION"}, rows)
+		},
+	}
+}
+
+// configProblems reports validation problems as a usage error.
+func configProblems(problems []config.Problem, message string) error {
+	if output.Structured() {
+		output.Print(problems)
+	} else {
+		rows := make([][]string, len(problems))
+		for i, p := range problems {
+			rows[i] = []string{p.Where, p.Key, p.Error}
+		}
+		if err := output.PrintTable([]string{"WHERE", "KEY", "PROBLEM"}, rows); err != nil {
+			return err
+		}
+	}
+	return &client.ValidationError{Message: message}
+}
+
+func newConfigValidateCmd() *cobra.Command {
 	return &cobra.Command{
-		Use:   "get",
-		Short: "Show current configuration",
+		Use:   "validate",
+		Short: "Check the config file for unknown keys and invalid values",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			cfg, err := config.Load()
+			problems, err := config.ValidateFile()
 			if err != nil {
-				output.PrintError(fmt.Sprintf("Failed to load config: %v", err))
+				return &client.ValidationError{Message: fmt.Sprintf("Invalid config file %s: %v", config.ConfigPath(), err)}
+			}
+			if len(problems) > 0 {
+				return configProblems(problems, fmt.Sprintf("%d problem(s) in %s", len(problems), config.ConfigPath()))
+			}
+
+			if output.Structured() {
+				return output.Print([]config.Problem{})
+			}
+			output.PrintSuccess(fmt.Sprintf("%s is valid", config.ConfigPath()))
+			return nil
+		},
+	}
+}
+
+func newConfigEditCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "edit",
+		Short: "Edit the config file in your editor",
+		Long:  "Open the config file in the editor preference, $VISUAL or $EDITOR. The file is only replaced if the edited version is valid.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			f, err := config.LoadFile()
+			if err != nil {
+				return fmt.Errorf("Failed to load config: %w", err)
+			}
+			original, err := json.MarshalIndent(f, "", "  ")
+			if err != nil {
+				return err
+			}
+
+			tmp, err := os.CreateTemp("", "agenthq-config-*.json")
+			if err != nil {
+				return err
+			}
+			defer tmp.Close()
+			if _, err := tmp.Write(original); err != nil {
+				return err
+			}
+			tmp.Close()
+
+			if err := runEditor(cmd.Context(), tmp.Name()); err != nil {
+				os.Remove(tmp.Name())
+				return err
+			}
+
+			edited, err := os.ReadFile(tmp.Name())
+			if err != nil {
+				return err
+			}
+			if bytes.Equal(bytes.TrimSpace(edited), bytes.TrimSpace(original)) {
+				os.Remove(tmp.Name())
+				output.PrintSuccess("No changes")
 				return nil
 			}
 
-			// Mask sensitive values
-			display := map[string]string{
-				"hub_url":  cfg.HubURL,
-				"org_id":   cfg.OrgID,
-				"agent_id": cfg.AgentID,
+			problems, err := config.ValidateData(edited)
+			if err != nil {
+				return &client.ValidationError{Message: fmt.Sprintf("Config not saved: %v\nYour edits are in %s", err, tmp.Name())}
+			}
+			if len(problems) > 0 {
+				return configProblems(problems, fmt.Sprintf("Config not saved: %d problem(s)\nYour edits are in %s", len(problems), tmp.Name()))
+			}
+
+			if err := os.MkdirAll(filepath.Dir(config.ConfigPath()), 0700); err != nil {
+				return err
 			}
-			if cfg.APIKey != "" {
-				display["api_key"] = cfg.APIKey[:12] + "..."
+			if err := os.WriteFile(config.ConfigPath(), edited, 0600); err != nil {
+				return fmt.Errorf("Failed to save config: %w", err)
 			}
-			if cfg.JWTToken != "" {
-				display["jwt_token"] = "***set***"
+			os.Remove(tmp.Name())
+			output.PrintSuccess(fmt.Sprintf("Saved %s", config.ConfigPath()))
+			return nil
+		},
+	}
+}
+
+func newConfigMigrateCredentialsCmd() *cobra.Command {
+	var to, helper, keyFile string
+	var usePassphrase bool
+
+	cmd := &cobra.Command{
+		Use:   "migrate-credentials",
+		Short: "Move stored API keys and tokens to another credential store",
+		Long: `Move the credentials of every profile to another credential store:
+
+  encrypted-file     AES-GCM encrypted credentials.enc next to config.json, keyed by
+                     a generated key file (--key-file) or a passphrase (--passphrase).
+                     The default key file sits beside credentials.enc, so anyone who
+                     can read the config directory can decrypt it; use --passphrase
+                     or a --key-file kept elsewhere for real protection
+  credential-helper  an external program run as '<helper> get|store|erase', like git's
+  plaintext          config.json itself (not recommended)
+
+Migrating to encrypted-file again re-encrypts it, e.g. to switch to a passphrase.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			settings := config.StoreSettings{Store: to, Helper: helper, KeyFile: keyFile}
+			switch to {
+			case config.StoreEncryptedFile:
+				if usePassphrase {
+					p, err := newPassphrase()
+					if err != nil {
+						return err
+					}
+					settings.Passphrase = p
+				}
+			case config.StoreCredentialHelper:
+				if helper == "" {
+					return &client.ValidationError{Message: "--helper is required with --to credential-helper"}
+				}
+			case config.StorePlaintext:
+			default:
+				return &client.ValidationError{Message: fmt.Sprintf("Unknown credential store %q (want encrypted-file, credential-helper or plaintext)", to)}
 			}
 
-			data, _ := json.MarshalIndent(display, "", "  ")
-			fmt.Println(string(data))
+			if err := config.MigrateCredentials(settings); err != nil {
+				return fmt.Errorf("Failed to migrate credentials: %w", err)
+			}
+			output.PrintSuccess(fmt.Sprintf("Credentials moved to %s", to))
 			return nil
 		},
 	}
+
+	cmd.Flags().StringVar(&to, "to", config.StoreEncryptedFile, "Credential store: encrypted-file, credential-helper or plaintext")
+	cmd.Flags().StringVar(&helper, "helper", "", "Credential helper command (with --to credential-helper)")
+	cmd.Flags().StringVar(&keyFile, "key-file", "", "Key file for the encrypted file (default: credentials.key next to config.json)")
+	cmd.Flags().BoolVar(&usePassphrase, "passphrase", false, "Encrypt with a passphrase from $AGENTHQ_PASSPHRASE or a prompt instead of a key file")
+
+	return cmd
+}
+
+// newPassphrase returns $AGENTHQ_PASSPHRASE or prompts for a passphrase
+// twice.
+func newPassphrase() (string, error) {
+	if p := os.Getenv(config.EnvPassphrase); p != "" {
+		return p, nil
+	}
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return "", &client.ValidationError{Message: fmt.Sprintf("--passphrase needs a terminal or %s", config.EnvPassphrase)}
+	}
+	p, err := config.ReadPassphrase("New passphrase: ")
+	if err != nil {
+		return "", err
+	}
+	confirm, err := config.ReadPassphrase("Repeat passphrase: ")
+	if err != nil {
+		return "", err
+	}
+	if p == "" || p != confirm {
+		return "", &client.ValidationError{Message: "Passphrases are empty or do not match"}
+	}
+	return p, nil
 }
 
 func NewSetupCmd() *cobra.Command {
@@ -105,19 +469,16 @@ func newSetupTestCmd() *cobra.Command {
 		Use:   "test",
 		Short: "Test hub connectivity",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, err := client.New()
+			c, err := agenthq.New()
 			if err != nil {
-				output.PrintError(fmt.Sprintf("Failed to create client: %v", err))
-				return nil
+				return fmt.Errorf("Failed to create client: %w", err)
 			}
 
 			cfg, _ := config.Load()
 			fmt.Printf("Testing connection to %s...\n", cfg.HubURL)
 
-			_, err = c.Get("/health", nil)
-			if err != nil {
-				output.PrintError(fmt.Sprintf("Connection failed: %v", err))
-				return nil
+			if _, err := c.Health(cmd.Context()); err != nil {
+				return fmt.Errorf("Connection failed: %w", err)
 			}
 
 			output.PrintSuccess("Hub is reachable")
@@ -1,14 +1,13 @@
 package commands
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"regexp"
 	"strings"
 
-	"github.com/Gahroot/agentHQ-cli/internal/common/client"
 	"github.com/Gahroot/agentHQ-cli/internal/common/config"
+	"github.com/Gahroot/agentHQ-cli/pkg/agenthq"
 	"github.com/Gahroot/agentHQ-cli/pkg/output"
 	"github.com/spf13/cobra"
 )
@@ -26,7 +25,7 @@ func parseInviteArg(arg string, fallbackURL string) (hubURL string, token string
 }
 
 func NewConnectCmd() *cobra.Command {
-	var hubURL, name string
+	var name string
 
 	cmd := &cobra.Command{
 		Use:   "connect <invite-url-or-token>",
@@ -40,10 +39,10 @@ Or a bare token with --hub-url:
   agenthq connect AHQ-xxxxx-xxxx --hub-url https://hub.example.com`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			parsedHub, token := parseInviteArg(args[0], hubURL)
+			parsedHub, token := parseInviteArg(args[0], "")
 
 			if parsedHub == "" {
-				parsedHub = "http://localhost:3000"
+				parsedHub = profileHubURL()
 			}
 
 			if name == "" {
@@ -55,27 +54,10 @@ Or a bare token with --hub-url:
 			}
 
 			// No auth token needed for redeem endpoint
-			c := client.NewWithToken(parsedHub, "")
-			resp, err := c.Post("/api/v1/auth/invites/redeem", map[string]string{
-				"token":     token,
-				"agentName": name,
-			})
+			c := agenthq.NewWithToken(parsedHub, "")
+			data, err := c.Auth.RedeemInvite(cmd.Context(), token, name)
 			if err != nil {
-				output.PrintError(fmt.Sprintf("Failed to redeem invite: %v", err))
-				return nil
-			}
-
-			var data struct {
-				Agent struct {
-					ID   string `json:"id"`
-					Name string `json:"name"`
-				} `json:"agent"`
-				APIKey string `json:"apiKey"`
-				OrgID  string `json:"orgId"`
-			}
-			if err := json.Unmarshal(resp.Data, &data); err != nil {
-				output.PrintError(fmt.Sprintf("Failed to parse response: %v", err))
-				return nil
+				return fmt.Errorf("Failed to redeem invite: %w", err)
 			}
 
 			cfg := &config.Config{
@@ -85,8 +67,7 @@ Or a bare token with --hub-url:
 				AgentID: data.Agent.ID,
 			}
 			if err := config.Save(cfg); err != nil {
-				output.PrintError(fmt.Sprintf("Failed to save config: %v", err))
-				return nil
+				return fmt.Errorf("Failed to save config: %w", err)
 			}
 
 			output.PrintSuccess(fmt.Sprintf("Connected as %s (ID: %s)", data.Agent.Name, data.Agent.ID))
@@ -95,7 +76,6 @@ Or a bare token with --hub-url:
 		},
 	}
 
-	cmd.Flags().StringVar(&hubURL, "hub-url", "", "Hub URL (only needed with bare tokens, not invite URLs)")
 	cmd.Flags().StringVar(&name, "name", "", "Agent name (default: hostname-based)")
 
 	return cmd
@@ -0,0 +1,175 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Gahroot/agentHQ-cli/internal/common/client"
+	"github.com/Gahroot/agentHQ-cli/internal/common/config"
+	"github.com/Gahroot/agentHQ-cli/pkg/output"
+	"github.com/spf13/cobra"
+)
+
+func NewContextCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "context",
+		Short: "Manage named hub profiles",
+		Long:  "Manage named profiles, each with its own hub URL and credentials. Login and connect write into the active profile; select one for a single command with --profile.",
+	}
+
+	cmd.AddCommand(newContextListCmd())
+	cmd.AddCommand(newContextCurrentCmd())
+	cmd.AddCommand(newContextUseCmd())
+	cmd.AddCommand(newContextRenameCmd())
+	cmd.AddCommand(newContextDeleteCmd())
+
+	return cmd
+}
+
+type contextInfo struct {
+	Name    string `json:"name"`
+	Current bool   `json:"current"`
+	HubURL  string `json:"hub_url"`
+	OrgID   string `json:"org_id"`
+	AgentID string `json:"agent_id"`
+	Auth    string `json:"auth"`
+}
+
+// profileAuth names the kind of credentials a profile has, in the store or
+// still in config.json. Locked means they could not be read, such as a
+// passphrase-protected store without $AGENTHQ_PASSPHRASE.
+func profileAuth(store config.CredentialStore, name string, p *config.Config) string {
+	switch {
+	case p.APIKey != "":
+		return "agent"
+	case p.JWTToken != "":
+		return "user"
+	}
+	creds, err := store.Get(name)
+	switch {
+	case err != nil:
+		return "locked"
+	case creds.APIKey != "":
+		return "agent"
+	case creds.JWTToken != "":
+		return "user"
+	default:
+		return "none"
+	}
+}
+
+func newContextListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List profiles",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			f, err := config.LoadFile()
+			if err != nil {
+				return fmt.Errorf("Failed to load config: %w", err)
+			}
+
+			store, err := f.Store()
+			if err != nil {
+				return fmt.Errorf("Failed to load config: %w", err)
+			}
+
+			active := f.Active()
+			contexts := make([]contextInfo, 0, len(f.Profiles))
+			for _, name := range f.Names() {
+				p := f.Profiles[name]
+				contexts = append(contexts, contextInfo{
+					Name:    name,
+					Current: name == active,
+					HubURL:  p.HubURL,
+					OrgID:   p.OrgID,
+					AgentID: p.AgentID,
+					Auth:    profileAuth(store, name, p),
+				})
+			}
+
+			if output.Structured() {
+				return output.Print(contexts)
+			}
+
+			if len(contexts) == 0 {
+				fmt.Println("No profiles. Run 'agenthq connect' or 'agenthq auth login' to create one.")
+				return nil
+			}
+			rows := make([][]string, len(contexts))
+			for i, ctx := range contexts {
+				current := ""
+				if ctx.Current {
+					current = "*"
+				}
+				rows[i] = []string{current, ctx.Name, ctx.HubURL, ctx.OrgID, ctx.Auth}
+			}
+			return output.PrintTable([]string{"CURRENT", "NAME", "HUB", "ORG", "AUTH"}, rows)
+		},
+	}
+}
+
+func newContextCurrentCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "current",
+		Short: "Show the active profile",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			f, err := config.LoadFile()
+			if err != nil {
+				return fmt.Errorf("Failed to load config: %w", err)
+			}
+
+			if output.Structured() {
+				return output.Print(map[string]string{"name": f.Active()})
+			}
+			fmt.Println(f.Active())
+			return nil
+		},
+	}
+}
+
+func newContextUseCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "use <name>",
+		Short: "Switch the current profile",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return updateProfiles(config.UseProfile(args[0]), fmt.Sprintf("Switched to profile %q", args[0]))
+		},
+	}
+}
+
+func newContextRenameCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "rename <old> <new>",
+		Short: "Rename a profile",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return updateProfiles(config.RenameProfile(args[0], args[1]), fmt.Sprintf("Renamed profile %q to %q", args[0], args[1]))
+		},
+	}
+}
+
+func newContextDeleteCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "delete <name>",
+		Short: "Delete a profile and its credentials",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return updateProfiles(config.DeleteProfile(args[0]), fmt.Sprintf("Deleted profile %q", args[0]))
+		},
+	}
+}
+
+// updateProfiles reports the result of a profile change. A missing or
+// conflicting profile is a usage error.
+func updateProfiles(err error, success string) error {
+	var profileErr *config.ProfileError
+	if errors.As(err, &profileErr) {
+		return &client.ValidationError{Message: err.Error()}
+	}
+	if err != nil {
+		return fmt.Errorf("Failed to update config: %w", err)
+	}
+	output.PrintSuccess(success)
+	return nil
+}
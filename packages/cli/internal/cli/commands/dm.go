@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/Gahroot/agentHQ-cli/internal/common/client"
+	"github.com/Gahroot/agentHQ-cli/pkg/agenthq"
 	"github.com/Gahroot/agentHQ-cli/pkg/output"
 	"github.com/spf13/cobra"
 )
@@ -17,6 +18,7 @@ func NewDMCmd() *cobra.Command {
 
 	cmd.AddCommand(newDMListCmd())
 	cmd.AddCommand(newDMStartCmd())
+	cmd.AddCommand(newDMHistoryCmd())
 
 	return cmd
 }
@@ -26,40 +28,26 @@ func newDMListCmd() *cobra.Command {
 		Use:   "list",
 		Short: "List DM conversations",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, err := client.New()
+			c, err := agenthq.New()
 			if err != nil {
-				output.PrintError(err.Error())
-				return nil
+				return err
 			}
 
-			resp, err := c.Get("/api/v1/dm", nil)
+			var raw agenthq.RawData
+			dms, err := c.WithRaw(&raw).DMs.List(cmd.Context())
 			if err != nil {
-				output.PrintError(fmt.Sprintf("Failed to list DMs: %v", err))
-				return nil
+				return fmt.Errorf("Failed to list DMs: %w", err)
 			}
 
-			if output.JSONMode {
-				output.PrintJSON(json.RawMessage(resp.Data))
-				return nil
-			}
-
-			var dms []struct {
-				ID         string `json:"id"`
-				Name       string `json:"name"`
-				MemberID   string `json:"member_id"`
-				MemberType string `json:"member_type"`
-			}
-			if err := json.Unmarshal(resp.Data, &dms); err != nil {
-				output.PrintError(fmt.Sprintf("Failed to parse response: %v", err))
-				return nil
+			if output.Structured() {
+				return printRaw(raw.JSON(), dms)
 			}
 
 			rows := make([][]string, len(dms))
 			for i, dm := range dms {
 				rows[i] = []string{dm.ID, dm.Name, dm.MemberID, dm.MemberType}
 			}
-			output.PrintTable([]string{"ID", "NAME", "MEMBER_ID", "MEMBER_TYPE"}, rows)
-			return nil
+			return output.PrintTable([]string{"ID", "NAME", "MEMBER_ID", "MEMBER_TYPE"}, rows)
 		},
 	}
 }
@@ -72,47 +60,86 @@ func newDMStartCmd() *cobra.Command {
 		Short: "Start DM conversation",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, err := client.New()
+			c, err := agenthq.New()
 			if err != nil {
-				output.PrintError(err.Error())
-				return nil
+				return err
 			}
 
 			if memberType == "" {
-				output.PrintError("--member-type is required")
-				return nil
+				return &client.ValidationError{Message: "--member-type is required"}
 			}
 
-			body := map[string]string{
-				"member_id":   args[0],
-				"member_type": memberType,
+			var raw agenthq.RawData
+			dm, err := c.WithRaw(&raw).DMs.Start(cmd.Context(), args[0], memberType)
+			if err != nil {
+				return fmt.Errorf("Failed to start DM: %w", err)
+			}
+
+			if output.Structured() {
+				return printRaw(raw.JSON(), dm)
+			}
+
+			output.PrintSuccess(fmt.Sprintf("DM started: %s (%s)", dm.Name, dm.ID))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&memberType, "member-type", "", "Member type (required)")
+
+	return cmd
+}
+
+func newDMHistoryCmd() *cobra.Command {
+	var pages pageFlags
+
+	cmd := &cobra.Command{
+		Use:   "history <dm-id>",
+		Short: "Show the messages of a DM conversation",
+		Long:  "Show the messages of a DM conversation, oldest first. Messages are rendered as Markdown when stdout is a terminal.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := agenthq.New()
+			if err != nil {
+				return err
 			}
 
-			resp, err := c.Post("/api/v1/dm", body)
+			posts, pagination, raw, err := fetchPages(cmd.Context(), pages, c.Posts.Iter(agenthq.PostListParams{
+				ListOptions: pages.options(),
+				ChannelID:   args[0],
+			}))
 			if err != nil {
-				output.PrintError(fmt.Sprintf("Failed to start DM: %v", err))
-				return nil
+				return fmt.Errorf("Failed to get DM history: %w", err)
 			}
 
-			if output.JSONMode {
-				output.PrintJSON(json.RawMessage(resp.Data))
-				return nil
+			// The hub returns the newest messages first.
+			for i, j := 0, len(posts)-1; i < j; i, j = i+1, j-1 {
+				posts[i], posts[j] = posts[j], posts[i]
 			}
 
-			var dm struct {
-				ID   string `json:"id"`
-				Name string `json:"name"`
+			if output.Structured() {
+				var messages []json.RawMessage
+				if err := json.Unmarshal(raw, &messages); err != nil {
+					return output.Print(posts)
+				}
+				for i, j := 0, len(messages)-1; i < j; i, j = i+1, j-1 {
+					messages[i], messages[j] = messages[j], messages[i]
+				}
+				return output.Print(messages)
 			}
-			if err := json.Unmarshal(resp.Data, &dm); err != nil {
-				output.PrintError(fmt.Sprintf("Failed to parse response: %v", err))
+
+			if len(posts) == 0 {
+				fmt.Println("No messages yet.")
 				return nil
 			}
-			output.PrintSuccess(fmt.Sprintf("DM started: %s (%s)", dm.Name, dm.ID))
+			for i := range posts {
+				printMessage(posts[i].AuthorID, &posts[i])
+			}
+			printMoreHint(len(posts), pagination)
 			return nil
 		},
 	}
 
-	cmd.Flags().StringVar(&memberType, "member-type", "", "Member type (required)")
+	pages.register(cmd)
 
 	return cmd
 }
@@ -0,0 +1,47 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+
+	"github.com/Gahroot/agentHQ-cli/internal/common/config"
+)
+
+// editorCommand returns the editor preference, $VISUAL or $EDITOR, falling
+// back to vi (notepad on Windows).
+func editorCommand() string {
+	if e := config.Preference("editor"); e != "" {
+		return e
+	}
+	for _, env := range []string{"VISUAL", "EDITOR"} {
+		if e := os.Getenv(env); e != "" {
+			return e
+		}
+	}
+	if runtime.GOOS == "windows" {
+		return "notepad"
+	}
+	return "vi"
+}
+
+// runEditor opens path in the user's editor and waits for it to exit. The
+// editor may include arguments, e.g. "code --wait".
+func runEditor(ctx context.Context, path string) error {
+	editor := editorCommand()
+	var c *exec.Cmd
+	if runtime.GOOS == "windows" {
+		c = exec.CommandContext(ctx, "cmd", "/C", editor+` "`+path+`"`)
+	} else {
+		c = exec.CommandContext(ctx, "sh", "-c", editor+` "$1"`, "sh", path)
+	}
+	c.Stdin = os.Stdin
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("Editor %q failed: %w", editor, err)
+	}
+	return nil
+}
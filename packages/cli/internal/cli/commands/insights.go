@@ -1,11 +1,11 @@
 package commands
 
 import (
-	"encoding/json"
 	"fmt"
 	"strconv"
 
 	"github.com/Gahroot/agentHQ-cli/internal/common/client"
+	"github.com/Gahroot/agentHQ-cli/pkg/agenthq"
 	"github.com/Gahroot/agentHQ-cli/pkg/output"
 	"github.com/spf13/cobra"
 )
@@ -25,7 +25,7 @@ func NewInsightsCmd() *cobra.Command {
 func newInsightsGenerateCmd() *cobra.Command {
 	var insightType string
 	var title string
-	var content string
+	var body contentFlags
 	var confidence float64
 
 	cmd := &cobra.Command{
@@ -33,54 +33,37 @@ func newInsightsGenerateCmd() *cobra.Command {
 		Short: "Generate insight",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if insightType == "" {
-				output.PrintError("--type is required (trend/performance/recommendation/summary/anomaly)")
-				return nil
+				return &client.ValidationError{Message: "--type is required (trend/performance/recommendation/summary/anomaly)"}
 			}
 			if title == "" {
-				output.PrintError("--title is required")
-				return nil
-			}
-			if content == "" {
-				output.PrintError("--content is required")
-				return nil
+				return &client.ValidationError{Message: "--title is required"}
 			}
 
-			c, err := client.New()
+			c, err := agenthq.New()
 			if err != nil {
-				output.PrintError(err.Error())
-				return nil
+				return err
 			}
 
-			body := map[string]interface{}{
-				"type":    insightType,
-				"title":   title,
-				"content": content,
-			}
-			if confidence > 0 {
-				body["confidence"] = confidence
+			content, err := body.get(cmd, "Type: "+insightType, "Title: "+title)
+			if err != nil {
+				return err
 			}
 
-			resp, err := c.Post("/api/v1/insights/generate", body)
+			var raw agenthq.RawData
+			insight, err := c.WithRaw(&raw).Insights.Generate(cmd.Context(), agenthq.InsightGenerateParams{
+				Type:       insightType,
+				Title:      title,
+				Content:    content,
+				Confidence: confidence,
+			})
 			if err != nil {
-				output.PrintError(fmt.Sprintf("Failed to generate insight: %v", err))
-				return nil
+				return fmt.Errorf("Failed to generate insight: %w", err)
 			}
 
-			if output.JSONMode {
-				output.PrintJSON(json.RawMessage(resp.Data))
-				return nil
+			if output.Structured() {
+				return printRaw(raw.JSON(), insight)
 			}
 
-			var insight struct {
-				ID         string  `json:"id"`
-				Type       string  `json:"type"`
-				Title      string  `json:"title"`
-				Confidence float64 `json:"confidence"`
-			}
-			if err := json.Unmarshal(resp.Data, &insight); err != nil {
-				output.PrintError(fmt.Sprintf("Failed to parse response: %v", err))
-				return nil
-			}
 			output.PrintSuccess(fmt.Sprintf("Insight generated: %s (%s)", insight.Title, insight.ID))
 			return nil
 		},
@@ -88,12 +71,11 @@ func newInsightsGenerateCmd() *cobra.Command {
 
 	cmd.Flags().StringVar(&insightType, "type", "", "Insight type (trend/performance/recommendation/summary/anomaly)")
 	cmd.Flags().StringVar(&title, "title", "", "Insight title")
-	cmd.Flags().StringVar(&content, "content", "", "Insight content")
+	body.register(cmd, "Insight content")
 	cmd.Flags().Float64Var(&confidence, "confidence", 0, "Confidence score (0-1)")
 
 	_ = cmd.MarkFlagRequired("type")
 	_ = cmd.MarkFlagRequired("title")
-	_ = cmd.MarkFlagRequired("content")
 
 	return cmd
 }
@@ -101,45 +83,28 @@ func newInsightsGenerateCmd() *cobra.Command {
 func newInsightsListCmd() *cobra.Command {
 	var insightType string
 	var since string
+	var pages pageFlags
 
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List insights",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, err := client.New()
+			c, err := agenthq.New()
 			if err != nil {
-				output.PrintError(err.Error())
-				return nil
-			}
-
-			params := make(map[string]string)
-			if insightType != "" {
-				params["type"] = insightType
-			}
-			if since != "" {
-				params["since"] = since
+				return err
 			}
 
-			resp, err := c.Get("/api/v1/insights", params)
+			insights, pagination, raw, err := fetchPages(cmd.Context(), pages, c.Insights.Iter(agenthq.InsightListParams{
+				ListOptions: pages.options(),
+				Type:        insightType,
+				Since:       since,
+			}))
 			if err != nil {
-				output.PrintError(fmt.Sprintf("Failed to list insights: %v", err))
-				return nil
-			}
-
-			if output.JSONMode {
-				output.PrintJSON(json.RawMessage(resp.Data))
-				return nil
+				return fmt.Errorf("Failed to list insights: %w", err)
 			}
 
-			var insights []struct {
-				ID         string  `json:"id"`
-				Type       string  `json:"type"`
-				Title      string  `json:"title"`
-				Confidence float64 `json:"confidence"`
-			}
-			if err := json.Unmarshal(resp.Data, &insights); err != nil {
-				output.PrintError(fmt.Sprintf("Failed to parse response: %v", err))
-				return nil
+			if output.Structured() {
+				return printRaw(raw, insights)
 			}
 
 			rows := make([][]string, len(insights))
@@ -150,8 +115,17 @@ func newInsightsListCmd() *cobra.Command {
 				}
 				rows[i] = []string{insight.ID, insight.Type, insight.Title, confidenceStr}
 			}
-			output.PrintTable([]string{"ID", "TYPE", "TITLE", "CONFIDENCE"}, rows)
+			if err := output.PrintTable([]string{"ID", "TYPE", "TITLE", "CONFIDENCE"}, rows); err != nil {
+				return err
+			}
+			printMoreHint(len(insights), pagination)
 			return nil
 		},
 	}
+
+	cmd.Flags().StringVar(&insightType, "type", "", "Filter by type")
+	cmd.Flags().StringVar(&since, "since", "", "ISO 8601 start time")
+	pages.register(cmd)
+
+	return cmd
 }
@@ -0,0 +1,149 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/Gahroot/agentHQ-cli/internal/common/client"
+	"github.com/spf13/cobra"
+)
+
+// metaFlags holds --meta and --meta-file for commands that send metadata.
+type metaFlags struct {
+	pairs []string
+	file  string
+}
+
+func (f *metaFlags) register(cmd *cobra.Command) {
+	cmd.Flags().StringArrayVar(&f.pairs, "meta", nil, "Metadata key=value; values are JSON if they parse, else strings, and dotted keys nest (repeatable)")
+	cmd.Flags().StringVar(&f.file, "meta-file", "", "Read metadata from a JSON object file (- for stdin)")
+}
+
+// given reports whether --meta or --meta-file was used.
+func (f *metaFlags) given(cmd *cobra.Command) bool {
+	return cmd.Flags().Changed("meta") || cmd.Flags().Changed("meta-file")
+}
+
+// get returns the metadata from --meta-file, or current without one, with
+// each --meta set over it. It returns nil if neither flag was given.
+func (f *metaFlags) get(cmd *cobra.Command, current map[string]interface{}) (map[string]interface{}, error) {
+	if !f.given(cmd) {
+		return nil, nil
+	}
+
+	meta := map[string]interface{}{}
+	if f.file == "" {
+		for k, v := range current {
+			meta[k] = v
+		}
+	} else {
+		var data []byte
+		var err error
+		if f.file == "-" {
+			if contentFromStdin(cmd) {
+				return nil, &client.ValidationError{Message: "--meta-file - and content from stdin cannot be used together"}
+			}
+			data, err = io.ReadAll(cmd.InOrStdin())
+		} else {
+			data, err = os.ReadFile(f.file)
+		}
+		if err != nil {
+			return nil, &client.ValidationError{Message: fmt.Sprintf("Failed to read metadata: %v", err)}
+		}
+		if err := json.Unmarshal(data, &meta); err != nil || meta == nil {
+			return nil, &client.ValidationError{Message: "--meta-file must contain a JSON object"}
+		}
+	}
+
+	for _, pair := range f.pairs {
+		if err := setMeta(meta, pair); err != nil {
+			return nil, err
+		}
+	}
+	return meta, nil
+}
+
+// contentFromStdin reports whether the command's content flags read stdin.
+func contentFromStdin(cmd *cobra.Command) bool {
+	for _, name := range []string{"content", "content-file"} {
+		if fl := cmd.Flags().Lookup(name); fl != nil && fl.Value.String() == "-" {
+			return true
+		}
+	}
+	return false
+}
+
+// setMeta sets a key=value pair in meta. The value is decoded as JSON if it
+// parses, so n=3, ok=true and tags=["a","b"] keep their types and n="3" is a
+// string; anything else is taken as a string. A dotted key sets a field of a
+// nested object.
+func setMeta(meta map[string]interface{}, pair string) error {
+	key, raw, ok := strings.Cut(pair, "=")
+	if !ok || key == "" {
+		return &client.ValidationError{Message: fmt.Sprintf("invalid --meta %q: expected key=value", pair)}
+	}
+
+	var value interface{} = raw
+	if json.Valid([]byte(raw)) {
+		dec := json.NewDecoder(strings.NewReader(raw))
+		dec.UseNumber()
+		var v interface{}
+		if dec.Decode(&v) == nil {
+			value = v
+		}
+	}
+
+	parts := strings.Split(key, ".")
+	m := meta
+	for i, part := range parts {
+		if part == "" {
+			return &client.ValidationError{Message: fmt.Sprintf("invalid --meta key %q", key)}
+		}
+		if i == len(parts)-1 {
+			m[part] = value
+			break
+		}
+		next, ok := m[part].(map[string]interface{})
+		if !ok {
+			if _, exists := m[part]; exists {
+				return &client.ValidationError{Message: fmt.Sprintf("invalid --meta key %q: %s is not an object", key, strings.Join(parts[:i+1], "."))}
+			}
+			next = map[string]interface{}{}
+			m[part] = next
+		}
+		m = next
+	}
+	return nil
+}
+
+// metaRows returns metadata as sorted key/value pairs for display. Strings
+// are shown as they are and other values as compact JSON.
+func metaRows(meta map[string]interface{}) [][]string {
+	keys := make([]string, 0, len(meta))
+	for k := range meta {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	rows := make([][]string, 0, len(keys))
+	for _, k := range keys {
+		var s string
+		switch v := meta[k].(type) {
+		case string:
+			s = v
+		default:
+			var b bytes.Buffer
+			enc := json.NewEncoder(&b)
+			enc.SetEscapeHTML(false)
+			enc.Encode(v)
+			s = strings.TrimSpace(b.String())
+		}
+		rows = append(rows, []string{k, s})
+	}
+	return rows
+}
@@ -0,0 +1,93 @@
+package commands
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestMetaFlags(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "meta.json")
+	os.WriteFile(file, []byte(`{"source":"file","n":1}`), 0600)
+	current := map[string]interface{}{"kept": true, "n": 0}
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    string
+		wantErr bool
+	}{
+		{"none", nil, `null`, false},
+		{"typed", []string{"--meta", "n=3", "--meta", "ok=true", "--meta", "s=hello", "--meta", "q=\"3\""}, `{"kept":true,"n":3,"ok":true,"q":"3","s":"hello"}`, false},
+		{"json", []string{"--meta", `tags=["a","b"]`, "--meta", "x=null"}, `{"kept":true,"n":0,"tags":["a","b"],"x":null}`, false},
+		{"not json", []string{"--meta", "v=1.2.3", "--meta", "e="}, `{"e":"","kept":true,"n":0,"v":"1.2.3"}`, false},
+		{"nested", []string{"--meta", "a.b=1", "--meta", "a.c=x"}, `{"a":{"b":1,"c":"x"},"kept":true,"n":0}`, false},
+		{"value with equals", []string{"--meta", "q=a=b"}, `{"kept":true,"n":0,"q":"a=b"}`, false},
+		{"file replaces current", []string{"--meta-file", file, "--meta", "n=2"}, `{"n":2,"source":"file"}`, false},
+		{"file stdin", []string{"--meta-file", "-"}, `{"from":"stdin"}`, false},
+		{"missing file", []string{"--meta-file", file + ".missing"}, ``, true},
+		{"no equals", []string{"--meta", "key"}, ``, true},
+		{"empty key", []string{"--meta", "=v"}, ``, true},
+		{"empty key part", []string{"--meta", "a..b=v"}, ``, true},
+		{"not an object", []string{"--meta", "kept.x=1"}, ``, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var meta metaFlags
+			cmd := &cobra.Command{Use: "test"}
+			meta.register(cmd)
+			cmd.SetIn(strings.NewReader(`{"from":"stdin"}`))
+			cmd.ParseFlags(tt.args)
+
+			got, err := meta.get(cmd, current)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("get() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			data, _ := json.Marshal(got)
+			if string(data) != tt.want {
+				t.Errorf("get() = %s, want %s", data, tt.want)
+			}
+		})
+	}
+}
+
+func TestMetaFlags_StdinConflict(t *testing.T) {
+	var body contentFlags
+	var meta metaFlags
+	cmd := newContentCmd(&body, "{}")
+	meta.register(cmd)
+	cmd.ParseFlags([]string{"--content", "-", "--meta-file", "-"})
+
+	if _, err := meta.get(cmd, nil); err == nil {
+		t.Error("expected error reading both content and metadata from stdin")
+	}
+}
+
+func TestMetaRows(t *testing.T) {
+	rows := metaRows(map[string]interface{}{
+		"source": "ci",
+		"build":  map[string]interface{}{"n": 42, "url": "https://ci/?a=1&b=2"},
+		"ok":     true,
+	})
+	want := [][]string{
+		{"build", `{"n":42,"url":"https://ci/?a=1&b=2"}`},
+		{"ok", "true"},
+		{"source", "ci"},
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("metaRows() = %v, want %v", rows, want)
+	}
+	for i := range want {
+		if rows[i][0] != want[i][0] || rows[i][1] != want[i][1] {
+			t.Errorf("row %d = %v, want %v", i, rows[i], want[i])
+		}
+	}
+}
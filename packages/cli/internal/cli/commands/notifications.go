@@ -1,10 +1,9 @@
 package commands
 
 import (
-	"encoding/json"
 	"fmt"
 
-	"github.com/Gahroot/agentHQ-cli/internal/common/client"
+	"github.com/Gahroot/agentHQ-cli/pkg/agenthq"
 	"github.com/Gahroot/agentHQ-cli/pkg/output"
 	"github.com/spf13/cobra"
 )
@@ -26,47 +25,28 @@ func NewNotificationsCmd() *cobra.Command {
 func newNotificationsListCmd() *cobra.Command {
 	var notificationType, readStatus string
 	var verbose bool
+	var pages pageFlags
 
 	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List notifications",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, err := client.New()
+			c, err := agenthq.New()
 			if err != nil {
-				output.PrintError(err.Error())
-				return nil
-			}
-
-			query := map[string]string{}
-			if notificationType != "" {
-				query["type"] = notificationType
-			}
-			if readStatus != "" {
-				query["read"] = readStatus
+				return err
 			}
 
-			resp, err := c.Get("/api/v1/notifications", query)
+			notifications, pagination, raw, err := fetchPages(cmd.Context(), pages, c.Notifications.Iter(agenthq.NotificationListParams{
+				ListOptions: pages.options(),
+				Type:        notificationType,
+				Read:        readStatus,
+			}))
 			if err != nil {
-				output.PrintError(fmt.Sprintf("Failed to list notifications: %v", err))
-				return nil
+				return fmt.Errorf("Failed to list notifications: %w", err)
 			}
 
-			if output.JSONMode {
-				output.PrintJSON(json.RawMessage(resp.Data))
-				return nil
-			}
-
-			var notifications []struct {
-				ID        string `json:"id"`
-				Type      string `json:"type"`
-				Read      bool   `json:"read"`
-				Title     string `json:"title"`
-				Body      string `json:"body"`
-				CreatedAt string `json:"created_at"`
-			}
-			if err := json.Unmarshal(resp.Data, &notifications); err != nil {
-				output.PrintError(fmt.Sprintf("Failed to parse response: %v", err))
-				return nil
+			if output.Structured() {
+				return printRaw(raw, notifications)
 			}
 
 			if len(notifications) == 0 {
@@ -90,18 +70,16 @@ func newNotificationsListCmd() *cobra.Command {
 				if displayText == "" && n.Body != "" {
 					displayText = n.Body
 				}
-				// Truncate for table display
-				if len(displayText) > maxLen {
-					displayText = displayText[:maxLen-3] + "..."
-				}
-				rows[i] = []string{n.ID[:8], n.Type, readStatus, displayText}
+				rows[i] = []string{n.ID[:8], n.Type, readStatus, output.Truncate(displayText, maxLen)}
 			}
-			output.PrintTable([]string{"ID", "TYPE", "READ", "TITLE"}, rows)
-
-			if resp.Pagination != nil && resp.Pagination.HasMore {
-				fmt.Printf("\nShowing %d of %d notifications. Use --json for full data.\n", len(notifications), resp.Pagination.Total)
+			if err := output.Print(&output.Table{
+				Headers:  []string{"ID", "TYPE", "READ", "TITLE"},
+				Rows:     rows,
+				Flexible: []string{"TITLE"},
+			}); err != nil {
+				return err
 			}
-
+			printMoreHint(len(notifications), pagination)
 			return nil
 		},
 	}
@@ -109,6 +87,7 @@ func newNotificationsListCmd() *cobra.Command {
 	cmd.Flags().StringVar(&notificationType, "type", "", "Filter by notification type")
 	cmd.Flags().StringVar(&readStatus, "read", "", "Filter by read status (true/false)")
 	cmd.Flags().BoolVar(&verbose, "verbose", false, "Show longer content in table display")
+	pages.register(cmd)
 
 	return cmd
 }
@@ -118,35 +97,25 @@ func newNotificationsUnreadCmd() *cobra.Command {
 		Use:   "unread",
 		Short: "Show unread notification count",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, err := client.New()
+			c, err := agenthq.New()
 			if err != nil {
-				output.PrintError(err.Error())
-				return nil
+				return err
 			}
 
-			resp, err := c.Get("/api/v1/notifications/unread-count", nil)
+			var raw agenthq.RawData
+			count, err := c.WithRaw(&raw).Notifications.UnreadCount(cmd.Context())
 			if err != nil {
-				output.PrintError(fmt.Sprintf("Failed to get unread count: %v", err))
-				return nil
+				return fmt.Errorf("Failed to get unread count: %w", err)
 			}
 
-			if output.JSONMode {
-				output.PrintJSON(json.RawMessage(resp.Data))
-				return nil
-			}
-
-			var result struct {
-				Count int `json:"count"`
-			}
-			if err := json.Unmarshal(resp.Data, &result); err != nil {
-				output.PrintError(fmt.Sprintf("Failed to parse response: %v", err))
-				return nil
+			if output.Structured() {
+				return printRaw(raw.JSON(), map[string]int{"count": count})
 			}
 
-			if result.Count == 0 {
+			if count == 0 {
 				fmt.Println("No unread notifications.")
 			} else {
-				fmt.Printf("You have %d unread notification%s.\n", result.Count, plural(result.Count))
+				fmt.Printf("You have %d unread notification%s.\n", count, plural(count))
 			}
 
 			return nil
@@ -162,17 +131,14 @@ func newNotificationsReadCmd() *cobra.Command {
 		Short: "Mark notification as read",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, err := client.New()
+			c, err := agenthq.New()
 			if err != nil {
-				output.PrintError(err.Error())
-				return nil
+				return err
 			}
 
 			id := args[0]
-			_, err = c.Patch(fmt.Sprintf("/api/v1/notifications/%s/read", id), nil)
-			if err != nil {
-				output.PrintError(fmt.Sprintf("Failed to mark notification as read: %v", err))
-				return nil
+			if err := c.Notifications.MarkRead(cmd.Context(), id); err != nil {
+				return fmt.Errorf("Failed to mark notification as read: %w", err)
 			}
 
 			output.PrintSuccess(fmt.Sprintf("Notification %s marked as read", id))
@@ -188,16 +154,13 @@ func newNotificationsReadAllCmd() *cobra.Command {
 		Use:   "read-all",
 		Short: "Mark all notifications as read",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, err := client.New()
+			c, err := agenthq.New()
 			if err != nil {
-				output.PrintError(err.Error())
-				return nil
+				return err
 			}
 
-			_, err = c.Post("/api/v1/notifications/read-all", nil)
-			if err != nil {
-				output.PrintError(fmt.Sprintf("Failed to mark all as read: %v", err))
-				return nil
+			if err := c.Notifications.MarkAllRead(cmd.Context()); err != nil {
+				return fmt.Errorf("Failed to mark all as read: %w", err)
 			}
 
 			output.PrintSuccess("All notifications marked as read")
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/Gahroot/agentHQ-cli/internal/common/client"
+	"github.com/Gahroot/agentHQ-cli/pkg/agenthq"
 	"github.com/Gahroot/agentHQ-cli/pkg/output"
 	"github.com/spf13/cobra"
 )
@@ -26,31 +27,19 @@ func newOrgGetCmd() *cobra.Command {
 		Use:   "get",
 		Short: "Get organization details",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, err := client.New()
+			c, err := agenthq.New()
 			if err != nil {
-				output.PrintError(err.Error())
-				return nil
+				return err
 			}
 
-			resp, err := c.Get("/api/v1/org", nil)
+			var raw agenthq.RawData
+			org, err := c.WithRaw(&raw).Org.Get(cmd.Context())
 			if err != nil {
-				output.PrintError(fmt.Sprintf("Failed to get organization: %v", err))
-				return nil
+				return fmt.Errorf("Failed to get organization: %w", err)
 			}
 
-			if output.JSONMode {
-				output.PrintJSON(json.RawMessage(resp.Data))
-				return nil
-			}
-
-			var org struct {
-				ID       string                 `json:"id"`
-				Name     string                 `json:"name"`
-				Settings map[string]interface{} `json:"settings"`
-			}
-			if err := json.Unmarshal(resp.Data, &org); err != nil {
-				output.PrintError(fmt.Sprintf("Failed to parse response: %v", err))
-				return nil
+			if output.Structured() {
+				return printRaw(raw.JSON(), org)
 			}
 
 			fmt.Printf("ID:\t%s\n", org.ID)
@@ -73,57 +62,32 @@ func newOrgUpdateCmd() *cobra.Command {
 		Use:   "update",
 		Short: "Update organization",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, err := client.New()
+			c, err := agenthq.New()
 			if err != nil {
-				output.PrintError(err.Error())
-				return nil
+				return err
 			}
 
-			body := make(map[string]interface{})
-			if name != "" {
-				body["name"] = name
-			}
+			params := agenthq.OrgUpdateParams{Name: name}
 			if settingsStr != "" {
-				var settings map[string]interface{}
-				if err := json.Unmarshal([]byte(settingsStr), &settings); err != nil {
-					output.PrintError(fmt.Sprintf("Invalid settings JSON: %v", err))
-					return nil
+				if err := json.Unmarshal([]byte(settingsStr), &params.Settings); err != nil {
+					return fmt.Errorf("Invalid settings JSON: %w", err)
 				}
-				body["settings"] = settings
 			}
 
-			if len(body) == 0 {
-				output.PrintError("At least one of --name or --settings must be provided")
-				return nil
+			if params.Name == "" && params.Settings == nil {
+				return &client.ValidationError{Message: "At least one of --name or --settings must be provided"}
 			}
 
-			resp, err := c.Patch("/api/v1/org", body)
+			var raw agenthq.RawData
+			org, err := c.WithRaw(&raw).Org.Update(cmd.Context(), params)
 			if err != nil {
-				output.PrintError(fmt.Sprintf("Failed to update organization: %v", err))
-				return nil
-			}
-
-			if output.JSONMode {
-				output.PrintJSON(json.RawMessage(resp.Data))
-				return nil
+				return fmt.Errorf("Failed to update organization: %w", err)
 			}
 
-			var org struct {
-				ID   string `json:"id"`
-				Name string `json:"name"`
-			}
-			if err := json.Unmarshal(resp.Data, &org); err != nil {
-				output.PrintError(fmt.Sprintf("Failed to parse response: %v", err))
-				return nil
+			if output.Structured() {
+				return printRaw(raw.JSON(), org)
 			}
 
-			parts := []string{}
-			if name != "" {
-				parts = append(parts, fmt.Sprintf("name to %s", name))
-			}
-			if settingsStr != "" {
-				parts = append(parts, "settings")
-			}
 			output.PrintSuccess(fmt.Sprintf("Organization updated: %s (%s)", org.Name, org.ID))
 			return nil
 		},
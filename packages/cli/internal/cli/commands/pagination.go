@@ -0,0 +1,56 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/Gahroot/agentHQ-cli/pkg/agenthq"
+	"github.com/spf13/cobra"
+)
+
+// allPagesLimit is the page size used with --all when --limit is not set;
+// it is the largest page the hub serves.
+const allPagesLimit = 100
+
+// pageFlags holds the --page, --limit and --all flags shared by list commands.
+type pageFlags struct {
+	page  int
+	limit int
+	all   bool
+}
+
+func (p *pageFlags) register(cmd *cobra.Command) {
+	cmd.Flags().IntVar(&p.page, "page", 1, "Page number to fetch")
+	cmd.Flags().IntVar(&p.limit, "limit", 0, "Results per page (hub default 20, max 100)")
+	cmd.Flags().BoolVar(&p.all, "all", false, "Fetch every page")
+}
+
+func (p pageFlags) options() agenthq.ListOptions {
+	opts := agenthq.ListOptions{Page: p.page, Limit: p.limit}
+	if p.all && opts.Limit == 0 {
+		opts.Limit = allPagesLimit
+	}
+	return opts
+}
+
+// fetchPages returns the requested page, or every page from it onwards with
+// --all, along with the hub's data for them.
+func fetchPages[T any](ctx context.Context, p pageFlags, it *agenthq.Iterator[T]) ([]T, *agenthq.Pagination, json.RawMessage, error) {
+	if p.all {
+		items, pagination, err := it.All(ctx)
+		return items, pagination, it.Raw(), err
+	}
+	if !it.Next(ctx) {
+		return nil, nil, nil, it.Err()
+	}
+	return it.Items(), it.Pagination(), it.Raw(), nil
+}
+
+// printMoreHint tells the user how to reach results beyond the current page.
+func printMoreHint(shown int, pagination *agenthq.Pagination) {
+	if pagination == nil || !pagination.HasMore {
+		return
+	}
+	fmt.Printf("\nShowing %d of %d. Use --page %d or --all for more.\n", shown, pagination.Total, pagination.Page+1)
+}
@@ -0,0 +1,45 @@
+package commands
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Gahroot/agentHQ-cli/pkg/agenthq"
+)
+
+func TestPageFlags_Options(t *testing.T) {
+	tests := []struct {
+		name  string
+		flags pageFlags
+		want  agenthq.ListOptions
+	}{
+		{"defaults", pageFlags{page: 1}, agenthq.ListOptions{Page: 1}},
+		{"explicit limit", pageFlags{page: 2, limit: 50}, agenthq.ListOptions{Page: 2, Limit: 50}},
+		{"all uses max page size", pageFlags{page: 1, all: true}, agenthq.ListOptions{Page: 1, Limit: allPagesLimit}},
+		{"all keeps explicit limit", pageFlags{page: 1, limit: 10, all: true}, agenthq.ListOptions{Page: 1, Limit: 10}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.flags.options(); got != tt.want {
+				t.Errorf("options() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFetchPages(t *testing.T) {
+	fetch := func(ctx context.Context, opts agenthq.ListOptions) ([]int, *agenthq.Pagination, error) {
+		return []int{opts.Page}, &agenthq.Pagination{Page: opts.Page, Total: 3, HasMore: opts.Page < 3}, nil
+	}
+
+	items, _, _, err := fetchPages(context.Background(), pageFlags{page: 2}, agenthq.NewIterator(agenthq.ListOptions{Page: 2}, fetch))
+	if err != nil || len(items) != 1 || items[0] != 2 {
+		t.Errorf("expected only page 2, got %v (err=%v)", items, err)
+	}
+
+	items, _, _, err = fetchPages(context.Background(), pageFlags{page: 1, all: true}, agenthq.NewIterator(agenthq.ListOptions{Page: 1}, fetch))
+	if err != nil || len(items) != 3 {
+		t.Errorf("expected all 3 pages, got %v (err=%v)", items, err)
+	}
+}
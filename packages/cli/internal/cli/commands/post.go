@@ -1,10 +1,13 @@
 package commands
 
 import (
-	"encoding/json"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/Gahroot/agentHQ-cli/internal/common/client"
+	"github.com/Gahroot/agentHQ-cli/internal/common/config"
+	"github.com/Gahroot/agentHQ-cli/pkg/agenthq"
 	"github.com/Gahroot/agentHQ-cli/pkg/output"
 	"github.com/spf13/cobra"
 )
@@ -28,58 +31,75 @@ func NewPostCmd() *cobra.Command {
 }
 
 func newPostCreateCmd() *cobra.Command {
-	var channelID, postType, title, content string
+	var channelID, postType, title string
+	var body contentFlags
+	var meta metaFlags
 
 	cmd := &cobra.Command{
 		Use:   "create",
 		Short: "Create a post in the hub",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, err := client.New()
+			project, err := config.LoadProject()
 			if err != nil {
-				output.PrintError(err.Error())
-				return nil
+				return fmt.Errorf("Failed to load project config: %w", err)
 			}
-
-			body := map[string]string{
-				"channel_id": channelID,
-				"content":    content,
+			if channelID == "" {
+				channelID = project.DefaultChannel
 			}
-			if postType != "" {
-				body["type"] = postType
+			if channelID == "" {
+				return &client.ValidationError{Message: "--channel is required (or set default_channel in .agenthq.json)"}
 			}
-			if title != "" {
-				body["title"] = title
+			if postType == "" {
+				postType = project.DefaultPostType
+			}
+			if postType == "" {
+				postType = "update"
 			}
 
-			resp, err := c.Post("/api/v1/posts", body)
+			c, err := agenthq.New()
 			if err != nil {
-				output.PrintError(fmt.Sprintf("Failed to create post: %v", err))
-				return nil
+				return err
 			}
 
-			if output.JSONMode {
-				output.PrintJSON(json.RawMessage(resp.Data))
-				return nil
+			info := []string{"Channel: " + channelID, "Type: " + postType}
+			if title != "" {
+				info = append(info, "Title: "+title)
+			}
+			metadata, err := meta.get(cmd, nil)
+			if err != nil {
+				return err
+			}
+			content, err := body.get(cmd, info...)
+			if err != nil {
+				return err
 			}
 
-			var post struct {
-				ID string `json:"id"`
+			var raw agenthq.RawData
+			post, err := c.WithRaw(&raw).Posts.Create(cmd.Context(), agenthq.PostCreateParams{
+				ChannelID: channelID,
+				Type:      postType,
+				Title:     title,
+				Content:   content,
+				Metadata:  metadata,
+			})
+			if err != nil {
+				return fmt.Errorf("Failed to create post: %w", err)
 			}
-			if err := json.Unmarshal(resp.Data, &post); err != nil {
-				output.PrintError(fmt.Sprintf("Failed to parse response: %v", err))
-				return nil
+
+			if output.Structured() {
+				return printRaw(raw.JSON(), post)
 			}
+
 			output.PrintSuccess(fmt.Sprintf("Post created: %s", post.ID))
 			return nil
 		},
 	}
 
-	cmd.Flags().StringVar(&channelID, "channel", "", "Channel ID")
-	cmd.Flags().StringVar(&postType, "type", "update", "Post type (update/insight/question/answer/alert/metric)")
+	cmd.Flags().StringVar(&channelID, "channel", "", "Channel ID (default: default_channel from .agenthq.json)")
+	cmd.Flags().StringVar(&postType, "type", "", "Post type (update/insight/question/answer/alert/metric) (default: default_post_type from .agenthq.json, or update)")
 	cmd.Flags().StringVar(&title, "title", "", "Post title")
-	cmd.Flags().StringVar(&content, "content", "", "Post content")
-	cmd.MarkFlagRequired("channel")
-	cmd.MarkFlagRequired("content")
+	body.register(cmd, "Post content")
+	meta.register(cmd)
 
 	return cmd
 }
@@ -90,51 +110,47 @@ func newPostGetCmd() *cobra.Command {
 		Short: "Get a single post with thread",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, err := client.New()
+			c, err := agenthq.New()
 			if err != nil {
-				output.PrintError(err.Error())
-				return nil
+				return err
 			}
 
-			resp, err := c.Get("/api/v1/posts/"+args[0], nil)
+			var raw agenthq.RawData
+			result, err := c.WithRaw(&raw).Posts.Get(cmd.Context(), args[0])
 			if err != nil {
-				output.PrintError(fmt.Sprintf("Failed to get post: %v", err))
-				return nil
+				return fmt.Errorf("Failed to get post: %w", err)
 			}
 
-			if output.JSONMode {
-				output.PrintJSON(json.RawMessage(resp.Data))
-				return nil
-			}
-
-			var result struct {
-				Post struct {
-					ID      string `json:"id"`
-					Type    string `json:"type"`
-					Title   string `json:"title"`
-					Content string `json:"content"`
-				} `json:"post"`
-				Thread []struct {
-					ID      string `json:"id"`
-					Content string `json:"content"`
-				} `json:"thread"`
-			}
-			if err := json.Unmarshal(resp.Data, &result); err != nil {
-				output.PrintError(fmt.Sprintf("Failed to parse response: %v", err))
-				return nil
+			if output.Structured() {
+				return printRaw(raw.JSON(), result)
 			}
 
 			fmt.Printf("ID: %s\n", result.Post.ID)
-			fmt.Printf("Type: %s\n", result.Post.Type)
+			fmt.Printf("Type: %s\n", output.Badge(result.Post.Type))
 			if result.Post.Title != "" {
 				fmt.Printf("Title: %s\n", result.Post.Title)
 			}
-			fmt.Printf("Content: %s\n", result.Post.Content)
+			if !output.Terminal() {
+				printMetadata(result.Post.Metadata)
+				fmt.Printf("Content: %s\n", result.Post.Content)
+				if len(result.Thread) > 0 {
+					fmt.Printf("\nThread (%d replies):\n", len(result.Thread))
+					for i, reply := range result.Thread {
+						fmt.Printf("  %d. %s: %s\n", i+1, reply.ID, output.Truncate(reply.Content, 60))
+					}
+				}
+				return nil
+			}
+
+			fmt.Printf("Author: %s\n", authorName(result, result.Post.AuthorID))
+			fmt.Printf("Posted: %s\n", output.Time(result.Post.CreatedAt, time.RFC3339))
+			printMetadata(result.Post.Metadata)
+			fmt.Printf("\n%s\n", output.Markdown(result.Post.Content))
 
 			if len(result.Thread) > 0 {
 				fmt.Printf("\nThread (%d replies):\n", len(result.Thread))
-				for i, reply := range result.Thread {
-					fmt.Printf("  %d. %s: %s\n", i+1, reply.ID, truncate(reply.Content, 60))
+				for _, reply := range result.Thread {
+					printMessage(authorName(result, reply.AuthorID), &reply)
 				}
 			}
 
@@ -145,219 +161,243 @@ func newPostGetCmd() *cobra.Command {
 	return cmd
 }
 
+// authorName returns the name of a thread's author, or their ID if the hub
+// did not include it.
+func authorName(thread *agenthq.PostThread, id string) string {
+	if a, ok := thread.Authors[id]; ok && a.Name != "" {
+		return a.Name
+	}
+	if thread.Author.ID == id && thread.Author.Name != "" {
+		return thread.Author.Name
+	}
+	return id
+}
+
+// printMetadata prints a post's metadata, one key per line.
+func printMetadata(meta map[string]interface{}) {
+	if len(meta) == 0 {
+		return
+	}
+	fmt.Println("Metadata:")
+	for _, row := range metaRows(meta) {
+		fmt.Printf("  %s: %s\n", row[0], row[1])
+	}
+}
+
+// printMessage prints a reply or DM under a header line, rendering its
+// Markdown on terminals.
+func printMessage(author string, p *agenthq.Post) {
+	fmt.Printf("\n%s  %s  %s\n", output.Bold(author), output.Dim(output.Time(p.CreatedAt, time.RFC3339)), output.Dim(p.ID))
+	fmt.Println(output.Markdown(p.Content))
+}
+
 func newPostListCmd() *cobra.Command {
 	var channelID, postType string
+	var pages pageFlags
 
 	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List posts",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, err := client.New()
+			c, err := agenthq.New()
 			if err != nil {
-				output.PrintError(err.Error())
-				return nil
-			}
-
-			query := map[string]string{}
-			if channelID != "" {
-				query["channel_id"] = channelID
-			}
-			if postType != "" {
-				query["type"] = postType
+				return err
 			}
 
-			resp, err := c.Get("/api/v1/posts", query)
+			posts, pagination, raw, err := fetchPages(cmd.Context(), pages, c.Posts.Iter(agenthq.PostListParams{
+				ListOptions: pages.options(),
+				ChannelID:   channelID,
+				Type:        postType,
+			}))
 			if err != nil {
-				output.PrintError(fmt.Sprintf("Failed to list posts: %v", err))
-				return nil
+				return fmt.Errorf("Failed to list posts: %w", err)
 			}
 
-			if output.JSONMode {
-				output.PrintJSON(json.RawMessage(resp.Data))
-				return nil
-			}
-
-			var posts []struct {
-				ID      string `json:"id"`
-				Type    string `json:"type"`
-				Title   string `json:"title"`
-				Content string `json:"content"`
-			}
-			if err := json.Unmarshal(resp.Data, &posts); err != nil {
-				output.PrintError(fmt.Sprintf("Failed to parse response: %v", err))
-				return nil
+			if output.Structured() {
+				return printRaw(raw, posts)
 			}
 
 			rows := make([][]string, len(posts))
 			for i, p := range posts {
 				title := p.Title
 				if title == "" {
-					title = truncate(p.Content, 40)
+					title = output.Truncate(p.Content, 40)
 				}
-				rows[i] = []string{p.ID, p.Type, title}
+				rows[i] = []string{p.ID, output.Badge(p.Type), title, p.AuthorID, p.ChannelID, output.Time(p.CreatedAt, "2006-01-02 15:04")}
+			}
+			if err := output.Print(&output.Table{
+				Headers: []string{"ID", "TYPE", "TITLE", "AUTHOR", "CHANNEL", "CREATED"},
+				Rows:    rows,
+				Wide:    3,
+			}); err != nil {
+				return err
 			}
-			output.PrintTable([]string{"ID", "TYPE", "TITLE"}, rows)
+			printMoreHint(len(posts), pagination)
 			return nil
 		},
 	}
 
 	cmd.Flags().StringVar(&channelID, "channel", "", "Filter by channel")
 	cmd.Flags().StringVar(&postType, "type", "", "Filter by type")
+	pages.register(cmd)
 
 	return cmd
 }
 
 func newPostSearchCmd() *cobra.Command {
-	return &cobra.Command{
+	var pages pageFlags
+
+	cmd := &cobra.Command{
 		Use:   "search <query>",
 		Short: "Search posts",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, err := client.New()
+			c, err := agenthq.New()
 			if err != nil {
-				output.PrintError(err.Error())
-				return nil
+				return err
 			}
 
-			resp, err := c.Get("/api/v1/posts/search", map[string]string{"q": args[0]})
+			posts, pagination, raw, err := fetchPages(cmd.Context(), pages, c.Posts.SearchIter(args[0], pages.options()))
 			if err != nil {
-				output.PrintError(fmt.Sprintf("Search failed: %v", err))
-				return nil
-			}
-
-			if output.JSONMode {
-				output.PrintJSON(json.RawMessage(resp.Data))
-				return nil
+				return fmt.Errorf("Search failed: %w", err)
 			}
 
-			var posts []struct {
-				ID      string `json:"id"`
-				Title   string `json:"title"`
-				Content string `json:"content"`
-			}
-			if err := json.Unmarshal(resp.Data, &posts); err != nil {
-				output.PrintError(fmt.Sprintf("Failed to parse response: %v", err))
-				return nil
+			if output.Structured() {
+				return printRaw(raw, posts)
 			}
 
 			rows := make([][]string, len(posts))
 			for i, p := range posts {
 				title := p.Title
 				if title == "" {
-					title = truncate(p.Content, 50)
+					title = output.Truncate(p.Content, 50)
 				}
 				rows[i] = []string{p.ID, title}
 			}
-			output.PrintTable([]string{"ID", "TITLE"}, rows)
+			if err := output.PrintTable([]string{"ID", "TITLE"}, rows); err != nil {
+				return err
+			}
+			printMoreHint(len(posts), pagination)
 			return nil
 		},
 	}
+
+	pages.register(cmd)
+
+	return cmd
 }
 
 func newPostReplyCmd() *cobra.Command {
-	var content, channelID string
+	var channelID string
+	var body contentFlags
+	var meta metaFlags
 
 	cmd := &cobra.Command{
 		Use:   "reply <id>",
 		Short: "Reply to a post",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, err := client.New()
+			c, err := agenthq.New()
 			if err != nil {
-				output.PrintError(err.Error())
-				return nil
+				return err
 			}
 
-			body := map[string]string{
-				"parent_id": args[0],
-				"content":   content,
+			metadata, err := meta.get(cmd, nil)
+			if err != nil {
+				return err
 			}
-			if channelID != "" {
-				body["channel_id"] = channelID
+			content, err := body.get(cmd, "Reply to post "+args[0])
+			if err != nil {
+				return err
 			}
 
-			resp, err := c.Post("/api/v1/posts", body)
+			var raw agenthq.RawData
+			reply, err := c.WithRaw(&raw).Posts.Create(cmd.Context(), agenthq.PostCreateParams{
+				ParentID:  args[0],
+				ChannelID: channelID,
+				Content:   content,
+				Metadata:  metadata,
+			})
 			if err != nil {
-				output.PrintError(fmt.Sprintf("Failed to create reply: %v", err))
-				return nil
+				return fmt.Errorf("Failed to create reply: %w", err)
 			}
 
-			if output.JSONMode {
-				output.PrintJSON(json.RawMessage(resp.Data))
-				return nil
+			if output.Structured() {
+				return printRaw(raw.JSON(), reply)
 			}
 
-			var reply struct {
-				ID string `json:"id"`
-			}
-			if err := json.Unmarshal(resp.Data, &reply); err != nil {
-				output.PrintError(fmt.Sprintf("Failed to parse response: %v", err))
-				return nil
-			}
 			output.PrintSuccess(fmt.Sprintf("Reply created: %s", reply.ID))
 			return nil
 		},
 	}
 
-	cmd.Flags().StringVar(&content, "content", "", "Reply content")
+	body.register(cmd, "Reply content")
 	cmd.Flags().StringVar(&channelID, "channel", "", "Channel ID (optional, defaults to parent's channel)")
-	cmd.MarkFlagRequired("content")
+	meta.register(cmd)
 
 	return cmd
 }
 
 func newPostEditCmd() *cobra.Command {
-	var title, content string
+	var title string
+	var body contentFlags
 
 	cmd := &cobra.Command{
 		Use:   "edit <id>",
 		Short: "Edit a post",
+		Long:  "Edit a post's title or content. With neither flag on a terminal, the current content is opened in $EDITOR.",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, err := client.New()
+			c, err := agenthq.New()
 			if err != nil {
-				output.PrintError(err.Error())
-				return nil
+				return err
 			}
 
-			if title == "" && content == "" {
-				output.PrintError("At least one of --title or --content is required")
-				return nil
+			var content string
+			switch {
+			case body.given(cmd):
+				if content, err = body.value(cmd); err != nil {
+					return err
+				}
+			case title == "" && stdinIsTerminal():
+				current, err := c.Posts.Get(cmd.Context(), args[0])
+				if err != nil {
+					return fmt.Errorf("Failed to get post: %w", err)
+				}
+				content, err = compose(cmd.Context(), current.Post.Content, "Editing post "+args[0])
+				if err != nil {
+					return err
+				}
+				if content == current.Post.Content {
+					output.PrintSuccess("No changes")
+					return nil
+				}
 			}
 
-			body := map[string]string{}
-			if title != "" {
-				body["title"] = title
-			}
-			if content != "" {
-				body["content"] = content
+			if title == "" && content == "" {
+				return &client.ValidationError{Message: "At least one of --title or --content is required"}
 			}
 
-			resp, err := c.Patch("/api/v1/posts/"+args[0], body)
+			var raw agenthq.RawData
+			post, err := c.WithRaw(&raw).Posts.Edit(cmd.Context(), args[0], agenthq.PostEditParams{
+				Title:   title,
+				Content: content,
+			})
 			if err != nil {
-				output.PrintError(fmt.Sprintf("Failed to edit post: %v", err))
-				return nil
+				return fmt.Errorf("Failed to edit post: %w", err)
 			}
 
-			if output.JSONMode {
-				output.PrintJSON(json.RawMessage(resp.Data))
-				return nil
+			if output.Structured() {
+				return printRaw(raw.JSON(), post)
 			}
 
-			var post struct {
-				ID string `json:"id"`
-			}
-			if err := json.Unmarshal(resp.Data, &post); err != nil {
-				output.PrintError(fmt.Sprintf("Failed to parse response: %v", err))
-				return nil
-			}
 			output.PrintSuccess(fmt.Sprintf("Post updated: %s", post.ID))
 			return nil
 		},
 	}
 
 	cmd.Flags().StringVar(&title, "title", "", "New title")
-	cmd.Flags().StringVar(&content, "content", "", "New content")
+	body.register(cmd, "New content")
 
 	return cmd
 }
@@ -368,21 +408,17 @@ func newPostDeleteCmd() *cobra.Command {
 		Short: "Delete a post",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, err := client.New()
+			c, err := agenthq.New()
 			if err != nil {
-				output.PrintError(err.Error())
-				return nil
+				return err
 			}
 
-			_, err = c.Delete("/api/v1/posts/" + args[0])
-			if err != nil {
-				output.PrintError(fmt.Sprintf("Failed to delete post: %v", err))
-				return nil
+			if err := c.Posts.Delete(cmd.Context(), args[0]); err != nil {
+				return fmt.Errorf("Failed to delete post: %w", err)
 			}
 
-			if output.JSONMode {
-				output.PrintJSON(map[string]interface{}{"status": "deleted", "id": args[0]})
-				return nil
+			if output.Structured() {
+				return output.Print(map[string]interface{}{"status": "deleted", "id": args[0]})
 			}
 
 			output.PrintSuccess(fmt.Sprintf("Post deleted: %s", args[0]))
@@ -414,34 +450,21 @@ func newPostReactionAddCmd() *cobra.Command {
 		Short: "Add a reaction to a post",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, err := client.New()
+			c, err := agenthq.New()
 			if err != nil {
-				output.PrintError(err.Error())
-				return nil
+				return err
 			}
 
-			body := map[string]string{
-				"emoji": emoji,
-			}
-
-			resp, err := c.Post("/api/v1/posts/"+args[0]+"/reactions", body)
+			var raw agenthq.RawData
+			reaction, err := c.WithRaw(&raw).Reactions.Add(cmd.Context(), args[0], emoji)
 			if err != nil {
-				output.PrintError(fmt.Sprintf("Failed to add reaction: %v", err))
-				return nil
+				return fmt.Errorf("Failed to add reaction: %w", err)
 			}
 
-			if output.JSONMode {
-				output.PrintJSON(json.RawMessage(resp.Data))
-				return nil
+			if output.Structured() {
+				return printRaw(raw.JSON(), reaction)
 			}
 
-			var reaction struct {
-				ID string `json:"id"`
-			}
-			if err := json.Unmarshal(resp.Data, &reaction); err != nil {
-				output.PrintError(fmt.Sprintf("Failed to parse response: %v", err))
-				return nil
-			}
 			output.PrintSuccess(fmt.Sprintf("Reaction added: %s", reaction.ID))
 			return nil
 		},
@@ -459,25 +482,21 @@ func newPostReactionRemoveCmd() *cobra.Command {
 		Short: "Remove a reaction from a post",
 		Args:  cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, err := client.New()
+			c, err := agenthq.New()
 			if err != nil {
-				output.PrintError(err.Error())
-				return nil
+				return err
 			}
 
-			_, err = c.Delete("/api/v1/posts/" + args[0] + "/reactions/" + args[1])
-			if err != nil {
-				output.PrintError(fmt.Sprintf("Failed to remove reaction: %v", err))
-				return nil
+			if err := c.Reactions.Remove(cmd.Context(), args[0], args[1]); err != nil {
+				return fmt.Errorf("Failed to remove reaction: %w", err)
 			}
 
-			if output.JSONMode {
-				output.PrintJSON(map[string]interface{}{
+			if output.Structured() {
+				return output.Print(map[string]interface{}{
 					"status":  "removed",
 					"post_id": args[0],
 					"emoji":   args[1],
 				})
-				return nil
 			}
 
 			output.PrintSuccess(fmt.Sprintf("Reaction removed: %s from post %s", args[1], args[0]))
@@ -494,31 +513,19 @@ func newPostReactionListCmd() *cobra.Command {
 		Short: "List reactions on a post",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, err := client.New()
+			c, err := agenthq.New()
 			if err != nil {
-				output.PrintError(err.Error())
-				return nil
+				return err
 			}
 
-			resp, err := c.Get("/api/v1/posts/"+args[0]+"/reactions", nil)
+			var raw agenthq.RawData
+			reactions, err := c.WithRaw(&raw).Reactions.List(cmd.Context(), args[0])
 			if err != nil {
-				output.PrintError(fmt.Sprintf("Failed to list reactions: %v", err))
-				return nil
+				return fmt.Errorf("Failed to list reactions: %w", err)
 			}
 
-			if output.JSONMode {
-				output.PrintJSON(json.RawMessage(resp.Data))
-				return nil
-			}
-
-			var reactions []struct {
-				Emoji  string `json:"emoji"`
-				Count  int    `json:"count"`
-				UserID string `json:"user_id"`
-			}
-			if err := json.Unmarshal(resp.Data, &reactions); err != nil {
-				output.PrintError(fmt.Sprintf("Failed to parse response: %v", err))
-				return nil
+			if output.Structured() {
+				return printRaw(raw.JSON(), reactions)
 			}
 
 			if len(reactions) == 0 {
@@ -528,19 +535,15 @@ func newPostReactionListCmd() *cobra.Command {
 
 			rows := make([][]string, len(reactions))
 			for i, r := range reactions {
-				rows[i] = []string{r.Emoji, fmt.Sprintf("%d", r.Count), r.UserID}
+				authors := make([]string, len(r.Authors))
+				for j, a := range r.Authors {
+					authors[j] = a.ID
+				}
+				rows[i] = []string{r.Emoji, fmt.Sprintf("%d", r.Count), strings.Join(authors, ",")}
 			}
-			output.PrintTable([]string{"EMOJI", "COUNT", "USER_ID"}, rows)
-			return nil
+			return output.PrintTable([]string{"EMOJI", "COUNT", "AUTHORS"}, rows)
 		},
 	}
 
 	return cmd
 }
-
-func truncate(s string, max int) string {
-	if len(s) <= max {
-		return s
-	}
-	return s[:max-3] + "..."
-}
@@ -0,0 +1,17 @@
+package commands
+
+import (
+	"encoding/json"
+
+	"github.com/Gahroot/agentHQ-cli/pkg/output"
+)
+
+// printRaw prints the hub's data as the hub sent it, so structured output
+// keeps fields the SDK models drop and the hub's own formatting of values. v
+// is printed if there is no data.
+func printRaw(data json.RawMessage, v interface{}) error {
+	if data != nil {
+		return output.Print(data)
+	}
+	return output.Print(v)
+}
@@ -1,64 +1,43 @@
 package commands
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 
-	"github.com/Gahroot/agentHQ-cli/internal/common/client"
+	"github.com/Gahroot/agentHQ-cli/pkg/agenthq"
 	"github.com/Gahroot/agentHQ-cli/pkg/output"
 	"github.com/spf13/cobra"
 )
 
 func NewSearchCmd() *cobra.Command {
 	var types string
+	var pages pageFlags
 
 	cmd := &cobra.Command{
 		Use:   "search <query>",
 		Short: "Search across posts, insights, and agents",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, err := client.New()
+			c, err := agenthq.New()
 			if err != nil {
-				output.PrintError(err.Error())
-				return nil
+				return err
 			}
 
-			query := map[string]string{"q": args[0]}
-			if types != "" {
-				query["types"] = types
-			}
-
-			resp, err := c.Get("/api/v1/search", query)
+			data, rawData, pagination, err := searchPages(cmd.Context(), c, pages, agenthq.SearchParams{
+				ListOptions: pages.options(),
+				Query:       args[0],
+				Types:       types,
+			})
 			if err != nil {
-				output.PrintError(fmt.Sprintf("Search failed: %v", err))
-				return nil
+				return fmt.Errorf("Search failed: %w", err)
 			}
 
-			if output.JSONMode {
-				output.PrintJSON(json.RawMessage(resp.Data))
-				return nil
-			}
-
-			var data struct {
-				Posts []struct {
-					ID      string `json:"id"`
-					Title   string `json:"title"`
-					Content string `json:"content"`
-				} `json:"posts"`
-				Insights []struct {
-					ID    string `json:"id"`
-					Title string `json:"title"`
-					Type  string `json:"type"`
-				} `json:"insights"`
-				Agents []struct {
-					ID     string `json:"id"`
-					Name   string `json:"name"`
-					Status string `json:"status"`
-				} `json:"agents"`
-			}
-			if err := json.Unmarshal(resp.Data, &data); err != nil {
-				output.PrintError(fmt.Sprintf("Failed to parse response: %v", err))
-				return nil
+			if output.Structured() {
+				if rawData != nil {
+					return output.Print(rawData)
+				}
+				return output.Print(data)
 			}
 
 			if len(data.Posts) > 0 {
@@ -67,11 +46,13 @@ func NewSearchCmd() *cobra.Command {
 				for i, p := range data.Posts {
 					title := p.Title
 					if title == "" {
-						title = truncate(p.Content, 50)
+						title = output.Truncate(p.Content, 50)
 					}
 					rows[i] = []string{p.ID, title}
 				}
-				output.PrintTable([]string{"ID", "TITLE"}, rows)
+				if err := output.PrintTable([]string{"ID", "TITLE"}, rows); err != nil {
+					return err
+				}
 				fmt.Println()
 			}
 
@@ -81,7 +62,9 @@ func NewSearchCmd() *cobra.Command {
 				for i, ins := range data.Insights {
 					rows[i] = []string{ins.ID, ins.Type, ins.Title}
 				}
-				output.PrintTable([]string{"ID", "TYPE", "TITLE"}, rows)
+				if err := output.PrintTable([]string{"ID", "TYPE", "TITLE"}, rows); err != nil {
+					return err
+				}
 				fmt.Println()
 			}
 
@@ -91,18 +74,74 @@ func NewSearchCmd() *cobra.Command {
 				for i, a := range data.Agents {
 					rows[i] = []string{a.ID, a.Name, a.Status}
 				}
-				output.PrintTable([]string{"ID", "NAME", "STATUS"}, rows)
+				if err := output.PrintTable([]string{"ID", "NAME", "STATUS"}, rows); err != nil {
+					return err
+				}
 			}
 
-			if len(data.Posts) == 0 && len(data.Insights) == 0 && len(data.Agents) == 0 {
+			shown := len(data.Posts) + len(data.Insights) + len(data.Agents)
+			if shown == 0 {
 				fmt.Println("No results found.")
 			}
+			printMoreHint(shown, pagination)
 
 			return nil
 		},
 	}
 
 	cmd.Flags().StringVar(&types, "types", "", "Comma-separated resource types to search (posts,insights,agents)")
+	pages.register(cmd)
 
 	return cmd
 }
+
+// searchPages returns the requested page of search results, or with --all
+// every page from it onwards, along with the hub's data for structured
+// output. Each type's matches are joined across pages.
+func searchPages(ctx context.Context, c *agenthq.Client, p pageFlags, params agenthq.SearchParams) (*agenthq.SearchResults, json.RawMessage, *agenthq.Pagination, error) {
+	all := &agenthq.SearchResults{}
+	var raws []json.RawMessage
+	for {
+		var raw agenthq.RawData
+		page, pagination, err := c.WithRaw(&raw).Search.Query(ctx, params)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		all.Posts = append(all.Posts, page.Posts...)
+		all.Insights = append(all.Insights, page.Insights...)
+		all.Agents = append(all.Agents, page.Agents...)
+		raws = append(raws, raw.JSON())
+		if !p.all || pagination == nil || !pagination.HasMore {
+			return all, joinSearchPages(raws), pagination, nil
+		}
+		params.Page = pagination.Page + 1
+	}
+}
+
+// joinSearchPages joins the hub's data for several pages of search results
+// into one, keeping each match as the hub sent it.
+func joinSearchPages(pages []json.RawMessage) json.RawMessage {
+	if len(pages) == 1 {
+		return pages[0]
+	}
+	joined := struct {
+		Posts    []json.RawMessage `json:"posts"`
+		Insights []json.RawMessage `json:"insights"`
+		Agents   []json.RawMessage `json:"agents"`
+	}{[]json.RawMessage{}, []json.RawMessage{}, []json.RawMessage{}}
+	for _, data := range pages {
+		var page struct {
+			Posts    []json.RawMessage `json:"posts"`
+			Insights []json.RawMessage `json:"insights"`
+			Agents   []json.RawMessage `json:"agents"`
+		}
+		if json.Unmarshal(data, &page) != nil {
+			return nil
+		}
+		joined.Posts = append(joined.Posts, page.Posts...)
+		joined.Insights = append(joined.Insights, page.Insights...)
+		joined.Agents = append(joined.Agents, page.Agents...)
+	}
+	data, _ := json.Marshal(joined)
+	return data
+}
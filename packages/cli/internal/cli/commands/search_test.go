@@ -0,0 +1,20 @@
+package commands
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestJoinSearchPages(t *testing.T) {
+	first := json.RawMessage(`{"posts":[{"id":"p1","extra":true}],"insights":[],"agents":[{"id":"a1"}]}`)
+	second := json.RawMessage(`{"posts":[{"id":"p2"}],"insights":[{"id":"i1"}],"agents":[]}`)
+
+	if got := joinSearchPages([]json.RawMessage{first}); string(got) != string(first) {
+		t.Errorf("expected a single page as sent, got %s", got)
+	}
+
+	want := `{"posts":[{"id":"p1","extra":true},{"id":"p2"}],"insights":[{"id":"i1"}],"agents":[{"id":"a1"}]}`
+	if got := joinSearchPages([]json.RawMessage{first, second}); string(got) != want {
+		t.Errorf("joinSearchPages() = %s, want %s", got, want)
+	}
+}
@@ -1,11 +1,11 @@
 package commands
 
 import (
-	"encoding/json"
 	"fmt"
-	"time"
 
 	"github.com/Gahroot/agentHQ-cli/internal/common/client"
+	"github.com/Gahroot/agentHQ-cli/internal/common/config"
+	"github.com/Gahroot/agentHQ-cli/pkg/agenthq"
 	"github.com/Gahroot/agentHQ-cli/pkg/output"
 	"github.com/spf13/cobra"
 )
@@ -25,63 +25,32 @@ func NewTaskCmd() *cobra.Command {
 	return cmd
 }
 
-// Task represents a task in the system
-type Task struct {
-	ID           string     `json:"id"`
-	Title        string     `json:"title"`
-	Description  string     `json:"description"`
-	Status       string     `json:"status"`
-	Priority     string     `json:"priority"`
-	AssignedTo   string     `json:"assigned_to"`
-	AssignedType string     `json:"assigned_type"`
-	ChannelID    string     `json:"channel_id"`
-	DueDate      *time.Time `json:"due_date"`
-	CreatedAt    time.Time  `json:"created_at"`
-	CompletedAt  *time.Time `json:"completed_at"`
-}
-
 func newTaskListCmd() *cobra.Command {
 	var status, priority, assignedTo, channel string
+	var pages pageFlags
 
 	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List tasks",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, err := client.New()
+			c, err := agenthq.New()
 			if err != nil {
-				output.PrintError(err.Error())
-				return nil
-			}
-
-			query := map[string]string{}
-			if status != "" {
-				query["status"] = status
-			}
-			if priority != "" {
-				query["priority"] = priority
-			}
-			if assignedTo != "" {
-				query["assigned_to"] = assignedTo
-			}
-			if channel != "" {
-				query["channel"] = channel
+				return err
 			}
 
-			resp, err := c.Get("/api/v1/tasks", query)
+			tasks, pagination, raw, err := fetchPages(cmd.Context(), pages, c.Tasks.Iter(agenthq.TaskListParams{
+				ListOptions: pages.options(),
+				Status:      status,
+				Priority:    priority,
+				AssignedTo:  assignedTo,
+				ChannelID:   channel,
+			}))
 			if err != nil {
-				output.PrintError(fmt.Sprintf("Failed to list tasks: %v", err))
-				return nil
+				return fmt.Errorf("Failed to list tasks: %w", err)
 			}
 
-			if output.JSONMode {
-				output.PrintJSON(json.RawMessage(resp.Data))
-				return nil
-			}
-
-			var tasks []Task
-			if err := json.Unmarshal(resp.Data, &tasks); err != nil {
-				output.PrintError(fmt.Sprintf("Failed to parse response: %v", err))
-				return nil
+			if output.Structured() {
+				return printRaw(raw, tasks)
 			}
 
 			if len(tasks) == 0 {
@@ -93,11 +62,19 @@ func newTaskListCmd() *cobra.Command {
 			for i, t := range tasks {
 				dueDate := ""
 				if t.DueDate != nil {
-					dueDate = t.DueDate.Format("2006-01-02")
+					dueDate = output.Time(*t.DueDate, "2006-01-02")
 				}
-				rows[i] = []string{t.ID, t.Title, t.Status, t.Priority, dueDate}
+				rows[i] = []string{t.ID, t.Title, output.Badge(t.Status), output.Badge(t.Priority), dueDate, t.AssignedTo, t.ChannelID, output.Time(t.CreatedAt, "2006-01-02 15:04")}
+			}
+			if err := output.Print(&output.Table{
+				Headers:  []string{"ID", "TITLE", "STATUS", "PRIORITY", "DUE DATE", "ASSIGNED TO", "CHANNEL", "CREATED"},
+				Rows:     rows,
+				Wide:     3,
+				Flexible: []string{"TITLE", "ASSIGNED TO", "CHANNEL"},
+			}); err != nil {
+				return err
 			}
-			output.PrintTable([]string{"ID", "TITLE", "STATUS", "PRIORITY", "DUE DATE"}, rows)
+			printMoreHint(len(tasks), pagination)
 			return nil
 		},
 	}
@@ -106,70 +83,67 @@ func newTaskListCmd() *cobra.Command {
 	cmd.Flags().StringVar(&priority, "priority", "", "Filter by priority")
 	cmd.Flags().StringVar(&assignedTo, "assigned-to", "", "Filter by assigned agent")
 	cmd.Flags().StringVar(&channel, "channel", "", "Filter by channel")
+	pages.register(cmd)
 
 	return cmd
 }
 
 func newTaskCreateCmd() *cobra.Command {
 	var description, status, priority, assignedTo, assignedType, channel, dueDate string
+	var meta metaFlags
 
 	cmd := &cobra.Command{
 		Use:   "create --title <title>",
 		Short: "Create a task",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, err := client.New()
+			c, err := agenthq.New()
 			if err != nil {
-				output.PrintError(err.Error())
-				return nil
+				return err
 			}
 
 			title, err := cmd.Flags().GetString("title")
 			if err != nil || title == "" {
-				output.PrintError("--title is required")
-				return nil
-			}
-
-			body := map[string]interface{}{
-				"title": title,
-			}
-			if description != "" {
-				body["description"] = description
+				return &client.ValidationError{Message: "--title is required"}
 			}
-			if status != "" {
-				body["status"] = status
-			}
-			if priority != "" {
-				body["priority"] = priority
-			}
-			if assignedTo != "" {
-				body["assigned_to"] = assignedTo
-			}
-			if assignedType != "" {
-				body["assigned_type"] = assignedType
+			metadata, err := meta.get(cmd, nil)
+			if err != nil {
+				return err
 			}
-			if channel != "" {
-				body["channel"] = channel
+
+			project, err := config.LoadProject()
+			if err != nil {
+				return fmt.Errorf("Failed to load project config: %w", err)
 			}
-			if dueDate != "" {
-				body["due_date"] = dueDate
+			if assignedTo == "" && project.DefaultTaskAssignee != "" {
+				assignedTo = project.DefaultTaskAssignee
+				if assignedType == "" {
+					assignedType = "agent"
+				}
 			}
-
-			resp, err := c.Post("/api/v1/tasks", body)
+			if channel == "" {
+				channel = project.DefaultChannel
+			}
+
+			var raw agenthq.RawData
+			task, err := c.WithRaw(&raw).Tasks.Create(cmd.Context(), agenthq.TaskCreateParams{
+				Title:        title,
+				Description:  description,
+				Status:       status,
+				Priority:     priority,
+				AssignedTo:   assignedTo,
+				AssignedType: assignedType,
+				ChannelID:    channel,
+				DueDate:      dueDate,
+				Metadata:     metadata,
+			})
 			if err != nil {
-				output.PrintError(fmt.Sprintf("Failed to create task: %v", err))
-				return nil
+				return fmt.Errorf("Failed to create task: %w", err)
 			}
 
-			if output.JSONMode {
-				output.PrintJSON(json.RawMessage(resp.Data))
-				return nil
+			if output.Structured() {
+				return printRaw(raw.JSON(), task)
 			}
 
-			var task Task
-			if err := json.Unmarshal(resp.Data, &task); err != nil {
-				output.PrintError(fmt.Sprintf("Failed to parse response: %v", err))
-				return nil
-			}
 			output.PrintSuccess(fmt.Sprintf("Task created: %s (%s)", task.Title, task.ID))
 			return nil
 		},
@@ -179,10 +153,11 @@ func newTaskCreateCmd() *cobra.Command {
 	cmd.Flags().StringVar(&description, "description", "", "Task description")
 	cmd.Flags().StringVar(&status, "status", "", "Task status")
 	cmd.Flags().StringVar(&priority, "priority", "", "Task priority")
-	cmd.Flags().StringVar(&assignedTo, "assigned-to", "", "Assigned agent ID")
+	cmd.Flags().StringVar(&assignedTo, "assigned-to", "", "Assigned agent ID (default: default_task_assignee from .agenthq.json)")
 	cmd.Flags().StringVar(&assignedType, "assigned-type", "", "Assignment type")
-	cmd.Flags().StringVar(&channel, "channel", "", "Channel ID")
+	cmd.Flags().StringVar(&channel, "channel", "", "Channel ID (default: default_channel from .agenthq.json)")
 	cmd.Flags().StringVar(&dueDate, "due-date", "", "Due date (ISO 8601)")
+	meta.register(cmd)
 
 	cmd.MarkFlagRequired("title")
 
@@ -195,113 +170,102 @@ func newTaskGetCmd() *cobra.Command {
 		Short: "Get a task by ID",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, err := client.New()
+			c, err := agenthq.New()
 			if err != nil {
-				output.PrintError(err.Error())
-				return nil
+				return err
 			}
 
-			resp, err := c.Get(fmt.Sprintf("/api/v1/tasks/%s", args[0]), nil)
+			var raw agenthq.RawData
+			task, err := c.WithRaw(&raw).Tasks.Get(cmd.Context(), args[0])
 			if err != nil {
-				output.PrintError(fmt.Sprintf("Failed to get task: %v", err))
-				return nil
+				return fmt.Errorf("Failed to get task: %w", err)
 			}
 
-			if output.JSONMode {
-				output.PrintJSON(json.RawMessage(resp.Data))
-				return nil
-			}
-
-			var task Task
-			if err := json.Unmarshal(resp.Data, &task); err != nil {
-				output.PrintError(fmt.Sprintf("Failed to parse response: %v", err))
-				return nil
+			if output.Structured() {
+				return printRaw(raw.JSON(), task)
 			}
 
 			dueDate := "none"
 			if task.DueDate != nil {
-				dueDate = task.DueDate.Format("2006-01-02 15:04:05")
+				dueDate = output.Time(*task.DueDate, "2006-01-02 15:04:05")
 			}
 			completedAt := "none"
 			if task.CompletedAt != nil {
-				completedAt = task.CompletedAt.Format("2006-01-02 15:04:05")
+				completedAt = output.Time(*task.CompletedAt, "2006-01-02 15:04:05")
 			}
 
 			rows := [][]string{
 				{"ID", task.ID},
 				{"Title", task.Title},
 				{"Description", task.Description},
-				{"Status", task.Status},
-				{"Priority", task.Priority},
+				{"Status", output.Badge(task.Status)},
+				{"Priority", output.Badge(task.Priority)},
 				{"Assigned To", task.AssignedTo},
 				{"Assigned Type", task.AssignedType},
 				{"Channel ID", task.ChannelID},
 				{"Due Date", dueDate},
-				{"Created At", task.CreatedAt.Format("2006-01-02 15:04:05")},
+				{"Created At", output.Time(task.CreatedAt, "2006-01-02 15:04:05")},
 				{"Completed At", completedAt},
 			}
-			output.PrintTable([]string{"FIELD", "VALUE"}, rows)
-			return nil
+			for _, row := range metaRows(task.Metadata) {
+				rows = append(rows, []string{"meta." + row[0], row[1]})
+			}
+			return output.PrintTable([]string{"FIELD", "VALUE"}, rows)
 		},
 	}
 }
 
 func newTaskUpdateCmd() *cobra.Command {
 	var title, description, status, priority, assignedTo, assignedType, channel, dueDate string
+	var meta metaFlags
 
 	cmd := &cobra.Command{
 		Use:   "update <id>",
 		Short: "Update a task",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, err := client.New()
+			c, err := agenthq.New()
 			if err != nil {
-				output.PrintError(err.Error())
-				return nil
-			}
-
-			body := map[string]interface{}{}
-			if title != "" {
-				body["title"] = title
-			}
-			if description != "" {
-				body["description"] = description
-			}
-			if status != "" {
-				body["status"] = status
-			}
-			if priority != "" {
-				body["priority"] = priority
-			}
-			if assignedTo != "" {
-				body["assigned_to"] = assignedTo
-			}
-			if assignedType != "" {
-				body["assigned_type"] = assignedType
-			}
-			if channel != "" {
-				body["channel"] = channel
-			}
-			if dueDate != "" {
-				body["due_date"] = dueDate
+				return err
+			}
+
+			// The hub replaces metadata as a whole, so --meta alone is set
+			// over the task's current metadata.
+			var metadata map[string]interface{}
+			if meta.given(cmd) {
+				var current map[string]interface{}
+				if meta.file == "" {
+					task, err := c.Tasks.Get(cmd.Context(), args[0])
+					if err != nil {
+						return fmt.Errorf("Failed to get task: %w", err)
+					}
+					current = task.Metadata
+				}
+				if metadata, err = meta.get(cmd, current); err != nil {
+					return err
+				}
 			}
 
-			resp, err := c.Patch(fmt.Sprintf("/api/v1/tasks/%s", args[0]), body)
+			var raw agenthq.RawData
+			task, err := c.WithRaw(&raw).Tasks.Update(cmd.Context(), args[0], agenthq.TaskUpdateParams{
+				Title:        title,
+				Description:  description,
+				Status:       status,
+				Priority:     priority,
+				AssignedTo:   assignedTo,
+				AssignedType: assignedType,
+				ChannelID:    channel,
+				DueDate:      dueDate,
+				Metadata:     metadata,
+			})
 			if err != nil {
-				output.PrintError(fmt.Sprintf("Failed to update task: %v", err))
-				return nil
+				return fmt.Errorf("Failed to update task: %w", err)
 			}
 
-			if output.JSONMode {
-				output.PrintJSON(json.RawMessage(resp.Data))
-				return nil
+			if output.Structured() {
+				return printRaw(raw.JSON(), task)
 			}
 
-			var task Task
-			if err := json.Unmarshal(resp.Data, &task); err != nil {
-				output.PrintError(fmt.Sprintf("Failed to parse response: %v", err))
-				return nil
-			}
 			output.PrintSuccess(fmt.Sprintf("Task updated: %s (%s)", task.Title, task.ID))
 			return nil
 		},
@@ -315,6 +279,7 @@ func newTaskUpdateCmd() *cobra.Command {
 	cmd.Flags().StringVar(&assignedType, "assigned-type", "", "Assignment type")
 	cmd.Flags().StringVar(&channel, "channel", "", "Channel ID")
 	cmd.Flags().StringVar(&dueDate, "due-date", "", "Due date (ISO 8601)")
+	meta.register(cmd)
 
 	return cmd
 }
@@ -325,16 +290,13 @@ func newTaskDeleteCmd() *cobra.Command {
 		Short: "Delete a task",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, err := client.New()
+			c, err := agenthq.New()
 			if err != nil {
-				output.PrintError(err.Error())
-				return nil
+				return err
 			}
 
-			_, err = c.Delete(fmt.Sprintf("/api/v1/tasks/%s", args[0]))
-			if err != nil {
-				output.PrintError(fmt.Sprintf("Failed to delete task: %v", err))
-				return nil
+			if err := c.Tasks.Delete(cmd.Context(), args[0]); err != nil {
+				return fmt.Errorf("Failed to delete task: %w", err)
 			}
 
 			output.PrintSuccess(fmt.Sprintf("Task deleted: %s", args[0]))
@@ -0,0 +1,187 @@
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Gahroot/agentHQ-cli/internal/common/client"
+	"github.com/Gahroot/agentHQ-cli/pkg/output"
+	"github.com/Gahroot/agentHQ-cli/pkg/realtime"
+	"github.com/spf13/cobra"
+)
+
+// defaultWatchEvents are the events the hub broadcasts; control messages
+// like heartbeat_ack are only shown when asked for.
+var defaultWatchEvents = []string{
+	realtime.EventNotificationNew,
+	realtime.EventTaskNew,
+	realtime.EventTaskUpdated,
+}
+
+// watchEvents are the event names --events accepts.
+var watchEvents = []string{
+	realtime.EventNotificationNew,
+	realtime.EventTaskNew,
+	realtime.EventTaskUpdated,
+	realtime.EventSubscribed,
+	realtime.EventUnsubscribed,
+	realtime.EventHeartbeatAck,
+}
+
+func NewWatchCmd() *cobra.Command {
+	var channels, events []string
+
+	cmd := &cobra.Command{
+		Use:   "watch",
+		Short: "Stream live hub events",
+		Long:  "Stream hub events as they happen, one line per event, or as NDJSON with any structured --output format. Press Ctrl-C to stop.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if output.Template != "" || output.Query != "" {
+				return &client.ValidationError{Message: "--template and --query cannot be used with watch; use --output ndjson and filter each line instead"}
+			}
+			filter, err := newWatchFilter(events, channels)
+			if err != nil {
+				return err
+			}
+
+			connected := false
+			rt, err := realtime.ConnectWithConfig(cmd.Context(), realtime.Options{
+				Channels: channels,
+				OnConnect: func() {
+					if connected {
+						fmt.Fprintln(os.Stderr, "Reconnected.")
+					}
+					connected = true
+				},
+				OnDisconnect: func(err error) {
+					fmt.Fprintf(os.Stderr, "Connection lost (%v), reconnecting...\n", err)
+				},
+			})
+			if err != nil {
+				return fmt.Errorf("Failed to connect: %w", watchError(err))
+			}
+			defer rt.Close()
+
+			if !output.Structured() {
+				fmt.Fprintln(os.Stderr, "Watching for events. Press Ctrl-C to stop.")
+			}
+
+			for ev := range rt.Events() {
+				if !filter.keep(ev) {
+					continue
+				}
+				if output.Structured() {
+					writeEventJSON(os.Stdout, ev)
+				} else {
+					writeEventLine(os.Stdout, ev)
+				}
+			}
+
+			if err := rt.Err(); err != nil {
+				return watchError(err)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&channels, "channel", nil, "Only show task events from this channel ID (repeatable or comma-separated)")
+	cmd.Flags().StringSliceVar(&events, "events", nil, "Events to show (default notification:new,task:new,task:updated)")
+
+	return cmd
+}
+
+// watchFilter decides which events watch prints.
+type watchFilter struct {
+	events   map[string]bool
+	channels map[string]bool
+}
+
+// newWatchFilter builds a filter for --events and --channel. Unknown event
+// names are rejected, since they would never match anything.
+func newWatchFilter(events, channels []string) (*watchFilter, error) {
+	if len(events) == 0 {
+		events = defaultWatchEvents
+	}
+	f := &watchFilter{events: map[string]bool{}}
+	for _, e := range events {
+		e = strings.TrimSpace(e)
+		known := false
+		for _, name := range watchEvents {
+			known = known || e == name
+		}
+		if !known {
+			return nil, &client.ValidationError{Message: fmt.Sprintf("unknown event %q: must be one of %s", e, strings.Join(watchEvents, ", "))}
+		}
+		f.events[e] = true
+	}
+	if len(channels) > 0 {
+		f.channels = map[string]bool{}
+		for _, ch := range channels {
+			f.channels[strings.TrimSpace(ch)] = true
+		}
+	}
+	return f, nil
+}
+
+// keep reports whether ev should be printed. The hub sends task events to
+// the whole org, so with --channel they are matched on the task's channel.
+func (f *watchFilter) keep(ev realtime.Event) bool {
+	if !f.events[ev.Name] {
+		return false
+	}
+	if f.channels == nil || (ev.Name != realtime.EventTaskNew && ev.Name != realtime.EventTaskUpdated) {
+		return true
+	}
+	var payload struct {
+		Task struct {
+			ChannelID string `json:"channel_id"`
+		} `json:"task"`
+	}
+	json.Unmarshal(ev.Data, &payload)
+	return f.channels[payload.Task.ChannelID]
+}
+
+// watchError turns a rejected token into an AuthError with a hint.
+func watchError(err error) error {
+	if errors.Is(err, realtime.ErrAuth) {
+		return &client.AuthError{Message: fmt.Sprintf("%v. Run 'agenthq auth login' or 'agenthq connect' again", err)}
+	}
+	return err
+}
+
+// writeEventJSON writes ev as a single NDJSON line.
+func writeEventJSON(w io.Writer, ev realtime.Event) {
+	json.NewEncoder(w).Encode(struct {
+		Event      string          `json:"event"`
+		Data       json.RawMessage `json:"data,omitempty"`
+		ReceivedAt time.Time       `json:"received_at"`
+	}{ev.Name, ev.Data, ev.ReceivedAt})
+}
+
+// writeEventLine writes ev as a single human-readable line.
+func writeEventLine(w io.Writer, ev realtime.Event) {
+	fmt.Fprintf(w, "%s  %-16s  %s\n", ev.ReceivedAt.Format("15:04:05"), ev.Name, eventSummary(ev))
+}
+
+func eventSummary(ev realtime.Event) string {
+	switch ev.Name {
+	case realtime.EventTaskNew, realtime.EventTaskUpdated:
+		if t, err := ev.Task(); err == nil {
+			return fmt.Sprintf("%s  [%s/%s] %s", t.Task.ID, output.Badge(t.Task.Status), output.Badge(t.Task.Priority), t.Task.Title)
+		}
+	case realtime.EventNotificationNew:
+		if n, err := ev.Notification(); err == nil {
+			return fmt.Sprintf("%s  %s: %s", n.Notification.ID, n.Notification.Type, n.Notification.Title)
+		}
+	case realtime.EventSubscribed, realtime.EventUnsubscribed:
+		if s, err := ev.Subscription(); err == nil {
+			return s.Channel
+		}
+	}
+	return string(ev.Data)
+}
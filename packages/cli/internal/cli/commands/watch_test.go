@@ -0,0 +1,94 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Gahroot/agentHQ-cli/pkg/realtime"
+)
+
+func TestWriteEventLine(t *testing.T) {
+	at := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	tests := []struct {
+		name string
+		ev   realtime.Event
+		want string
+	}{
+		{
+			"task",
+			realtime.Event{Name: "task:new", Data: json.RawMessage(`{"task":{"id":"t1","title":"Ship it","status":"open","priority":"high"}}`), ReceivedAt: at},
+			"15:04:05  task:new          t1  [open/high] Ship it\n",
+		},
+		{
+			"notification",
+			realtime.Event{Name: "notification:new", Data: json.RawMessage(`{"recipientId":"a1","notification":{"id":"n1","type":"mention","title":"You were mentioned"}}`), ReceivedAt: at},
+			"15:04:05  notification:new  n1  mention: You were mentioned\n",
+		},
+		{
+			"unknown event falls back to raw data",
+			realtime.Event{Name: "custom", Data: json.RawMessage(`{"x":1}`), ReceivedAt: at},
+			"15:04:05  custom            {\"x\":1}\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			writeEventLine(&buf, tt.ev)
+			if buf.String() != tt.want {
+				t.Errorf("got %q, want %q", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteEventJSON(t *testing.T) {
+	var buf bytes.Buffer
+	writeEventJSON(&buf, realtime.Event{Name: "task:updated", Data: json.RawMessage(`{"task":{"id":"t1"}}`), ReceivedAt: time.Unix(0, 0).UTC()})
+
+	line := buf.String()
+	if strings.Count(line, "\n") != 1 {
+		t.Fatalf("expected exactly one line, got %q", line)
+	}
+	var parsed map[string]interface{}
+	if err := json.Unmarshal([]byte(line), &parsed); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if parsed["event"] != "task:updated" || parsed["data"] == nil {
+		t.Errorf("unexpected event %v", parsed)
+	}
+}
+
+func TestWatchFilter(t *testing.T) {
+	f, err := newWatchFilter(nil, []string{"c1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		ev   realtime.Event
+		want bool
+	}{
+		{"task in channel", realtime.Event{Name: "task:new", Data: json.RawMessage(`{"task":{"id":"t1","channel_id":"c1"}}`)}, true},
+		{"task in another channel", realtime.Event{Name: "task:updated", Data: json.RawMessage(`{"task":{"id":"t2","channel_id":"c2"}}`)}, false},
+		{"task without a channel", realtime.Event{Name: "task:new", Data: json.RawMessage(`{"task":{"id":"t3"}}`)}, false},
+		{"notification", realtime.Event{Name: "notification:new", Data: json.RawMessage(`{"notification":{"id":"n1"}}`)}, true},
+		{"not asked for", realtime.Event{Name: "heartbeat_ack"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := f.keep(tt.ev); got != tt.want {
+				t.Errorf("keep() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := newWatchFilter([]string{"task:created"}, nil); err == nil {
+		t.Error("expected error for an unknown event")
+	}
+}
@@ -0,0 +1,240 @@
+package commands
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Gahroot/agentHQ-cli/internal/common/client"
+	"github.com/Gahroot/agentHQ-cli/pkg/agenthq"
+	"github.com/Gahroot/agentHQ-cli/pkg/output"
+	"github.com/spf13/cobra"
+)
+
+func NewWebhookCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "webhook",
+		Short: "Webhook management commands",
+	}
+
+	cmd.AddCommand(newWebhookCreateCmd())
+	cmd.AddCommand(newWebhookListCmd())
+	cmd.AddCommand(newWebhookDeleteCmd())
+	cmd.AddCommand(newWebhookTestCmd())
+	cmd.AddCommand(newWebhookListenCmd())
+
+	return cmd
+}
+
+func newWebhookCreateCmd() *cobra.Command {
+	var webhookURL, secret string
+	var events []string
+
+	cmd := &cobra.Command{
+		Use:   "create --url <url> --events <events>",
+		Short: "Register a webhook",
+		Long:  "Register a webhook. Deliveries are signed with --secret; if none is given a secret is generated and shown once.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateWebhookEvents(events); err != nil {
+				return err
+			}
+
+			c, err := agenthq.New()
+			if err != nil {
+				return err
+			}
+
+			generated := secret == ""
+			if generated {
+				secret, err = generateWebhookSecret()
+				if err != nil {
+					return fmt.Errorf("Failed to generate secret: %w", err)
+				}
+			}
+
+			var raw agenthq.RawData
+			webhook, err := c.WithRaw(&raw).Webhooks.Create(cmd.Context(), agenthq.WebhookCreateParams{
+				URL:    webhookURL,
+				Events: events,
+				Secret: secret,
+			})
+			if err != nil {
+				return fmt.Errorf("Failed to create webhook: %w", err)
+			}
+
+			if output.Structured() {
+				// The hub does not echo the secret back.
+				if data, ok := withSecret(raw.JSON(), secret); ok {
+					return output.Print(data)
+				}
+				return output.Print(struct {
+					*agenthq.Webhook
+					Secret string `json:"secret"`
+				}{webhook, secret})
+			}
+
+			output.PrintSuccess(fmt.Sprintf("Webhook created: %s (%s)", webhook.ID, webhook.URL))
+			if generated {
+				fmt.Printf("Signing secret: %s\n", secret)
+				fmt.Println("Store it now; the hub will not show it again.")
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&webhookURL, "url", "", "URL to deliver events to (required)")
+	cmd.Flags().StringSliceVar(&events, "events", nil, "Events to deliver: "+strings.Join(agenthq.WebhookEvents, ", ")+" (required)")
+	cmd.Flags().StringVar(&secret, "secret", "", "Signing secret (default: generated)")
+
+	cmd.MarkFlagRequired("url")
+	cmd.MarkFlagRequired("events")
+
+	return cmd
+}
+
+func newWebhookListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List webhooks",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := agenthq.New()
+			if err != nil {
+				return err
+			}
+
+			var raw agenthq.RawData
+			webhooks, err := c.WithRaw(&raw).Webhooks.List(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("Failed to list webhooks: %w", err)
+			}
+
+			if output.Structured() {
+				return printRaw(raw.JSON(), webhooks)
+			}
+
+			if len(webhooks) == 0 {
+				output.PrintSuccess("No webhooks found")
+				return nil
+			}
+
+			rows := make([][]string, len(webhooks))
+			for i, w := range webhooks {
+				rows[i] = []string{w.ID, w.URL, strings.Join(w.Events, ","), yesNo(w.Active), yesNo(w.HasSecret)}
+			}
+			return output.PrintTable([]string{"ID", "URL", "EVENTS", "ACTIVE", "SIGNED"}, rows)
+		},
+	}
+}
+
+func newWebhookDeleteCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "delete <id>",
+		Short: "Delete a webhook",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := agenthq.New()
+			if err != nil {
+				return err
+			}
+
+			if err := c.Webhooks.Delete(cmd.Context(), args[0]); err != nil {
+				return fmt.Errorf("Failed to delete webhook: %w", err)
+			}
+
+			if output.Structured() {
+				return output.Print(map[string]interface{}{"status": "deleted", "id": args[0]})
+			}
+
+			output.PrintSuccess(fmt.Sprintf("Webhook deleted: %s", args[0]))
+			return nil
+		},
+	}
+}
+
+func newWebhookTestCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "test <id>",
+		Short: "Send a test ping to a webhook",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := agenthq.New()
+			if err != nil {
+				return err
+			}
+
+			var raw agenthq.RawData
+			result, err := c.WithRaw(&raw).Webhooks.Test(cmd.Context(), args[0])
+			if err != nil {
+				return fmt.Errorf("Failed to test webhook: %w", err)
+			}
+
+			if output.Structured() {
+				return printRaw(raw.JSON(), result)
+			}
+
+			output.PrintSuccess(result.Message)
+			return nil
+		},
+	}
+}
+
+// validateWebhookEvents checks events against the names the hub accepts.
+func validateWebhookEvents(events []string) error {
+	if len(events) == 0 {
+		return &client.ValidationError{Message: "at least one event is required"}
+	}
+	for _, e := range events {
+		valid := false
+		for _, known := range agenthq.WebhookEvents {
+			if e == known {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return &client.ValidationError{Message: fmt.Sprintf("unknown webhook event %q (valid: %s)", e, strings.Join(agenthq.WebhookEvents, ", "))}
+		}
+	}
+	return nil
+}
+
+// generateWebhookSecret returns a random 32-byte secret, hex encoded.
+func generateWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
+
+// withSecret adds a "secret" field to the JSON object data, keeping its
+// other fields and their order. It reports false if data is not an object.
+func withSecret(data json.RawMessage, secret string) (json.RawMessage, bool) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) < 2 || trimmed[0] != '{' || trimmed[len(trimmed)-1] != '}' {
+		return nil, false
+	}
+	value, err := json.Marshal(secret)
+	if err != nil {
+		return nil, false
+	}
+	body := bytes.TrimSpace(trimmed[1 : len(trimmed)-1])
+	out := append([]byte("{"), body...)
+	if len(body) > 0 {
+		out = append(out, ',')
+	}
+	out = append(out, `"secret":`...)
+	out = append(out, value...)
+	out = append(out, '}')
+	return out, json.Valid(out)
+}
@@ -0,0 +1,223 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Gahroot/agentHQ-cli/pkg/output"
+	"github.com/Gahroot/agentHQ-cli/pkg/webhook"
+	"github.com/spf13/cobra"
+)
+
+func newWebhookListenCmd() *cobra.Command {
+	var host, secret, forward, execCmd string
+	var port int
+
+	cmd := &cobra.Command{
+		Use:   "listen",
+		Short: "Receive webhook deliveries locally",
+		Long: `Run a local HTTP server that receives webhook deliveries, verifies their
+X-AgentHQ-Signature against --secret and logs each event. Deliveries can be
+forwarded to another URL with --forward, or piped to a command's stdin with
+--exec. Press Ctrl-C to stop.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			l := &webhookListener{
+				secret:  secret,
+				forward: forward,
+				exec:    execCmd,
+				json:    output.Structured(),
+				out:     os.Stdout,
+				log:     os.Stderr,
+				client:  &http.Client{Timeout: 10 * time.Second},
+			}
+
+			ln, err := net.Listen("tcp", net.JoinHostPort(host, strconv.Itoa(port)))
+			if err != nil {
+				return fmt.Errorf("Failed to listen: %w", err)
+			}
+			srv := &http.Server{Handler: l, ReadHeaderTimeout: 10 * time.Second}
+
+			fmt.Fprintf(os.Stderr, "Listening for webhooks on http://%s\n", ln.Addr())
+			if secret == "" {
+				fmt.Fprintln(os.Stderr, "Warning: no --secret given, signatures are not verified.")
+			}
+
+			errc := make(chan error, 1)
+			go func() { errc <- srv.Serve(ln) }()
+
+			select {
+			case err := <-errc:
+				return fmt.Errorf("Webhook listener failed: %w", err)
+			case <-cmd.Context().Done():
+				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				defer cancel()
+				return srv.Shutdown(ctx)
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&host, "host", "localhost", "Interface to listen on")
+	cmd.Flags().IntVar(&port, "port", 8080, "Port to listen on")
+	cmd.Flags().StringVar(&secret, "secret", "", "Webhook signing secret used to verify deliveries")
+	cmd.Flags().StringVar(&forward, "forward", "", "URL to forward each verified delivery to")
+	cmd.Flags().StringVar(&execCmd, "exec", "", "Shell command to run for each verified delivery, with the payload on stdin")
+
+	return cmd
+}
+
+// webhookListener receives, verifies and dispatches webhook deliveries.
+type webhookListener struct {
+	secret  string
+	forward string
+	exec    string
+	json    bool
+	out     io.Writer
+	log     io.Writer
+	client  *http.Client
+
+	// mu keeps the output of deliveries handled at the same time from
+	// interleaving.
+	mu sync.Mutex
+}
+
+// webhookDelivery is the NDJSON form of a received delivery.
+type webhookDelivery struct {
+	ReceivedAt time.Time       `json:"received_at"`
+	Event      string          `json:"event"`
+	Delivery   string          `json:"delivery"`
+	Verified   bool            `json:"verified"`
+	Payload    json.RawMessage `json:"payload"`
+}
+
+func (l *webhookListener) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, webhook.MaxBodySize))
+	if err != nil {
+		http.Error(w, "failed to read body", http.StatusBadRequest)
+		return
+	}
+
+	d := webhookDelivery{
+		ReceivedAt: time.Now(),
+		Event:      r.Header.Get(webhook.HeaderEvent),
+		Delivery:   r.Header.Get(webhook.HeaderDelivery),
+	}
+	if l.secret != "" {
+		if err := webhook.Verify(l.secret, body, r.Header.Get(webhook.HeaderSignature)); err != nil {
+			l.logf("✗ Rejected %s delivery %s: %v\n", d.Event, d.Delivery, err)
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		d.Verified = true
+	}
+	if !json.Valid(body) {
+		l.logf("✗ Rejected %s delivery %s: body is not JSON\n", d.Event, d.Delivery)
+		http.Error(w, "invalid JSON", http.StatusBadRequest)
+		return
+	}
+	d.Payload = body
+	l.print(d)
+
+	status := http.StatusOK
+	if l.forward != "" {
+		if err := l.forwardDelivery(r, body); err != nil {
+			l.logf("✗ Forward to %s failed: %v\n", l.forward, err)
+			status = http.StatusBadGateway
+		}
+	}
+	if l.exec != "" {
+		if err := l.runCommand(r.Context(), d, body); err != nil {
+			l.logf("✗ Command failed: %v\n", err)
+			status = http.StatusInternalServerError
+		}
+	}
+	w.WriteHeader(status)
+}
+
+func (l *webhookListener) print(d webhookDelivery) {
+	var buf bytes.Buffer
+	if l.json {
+		json.NewEncoder(&buf).Encode(d)
+	} else {
+		verified := "unverified"
+		if d.Verified {
+			verified = "verified"
+		}
+		fmt.Fprintf(&buf, "%s  %-16s  %s  %s\n", d.ReceivedAt.Format("15:04:05"), d.Event, d.Delivery, verified)
+		var pretty bytes.Buffer
+		if json.Indent(&pretty, d.Payload, "  ", "  ") == nil {
+			fmt.Fprintf(&buf, "  %s\n", pretty.String())
+		}
+	}
+	l.write(l.out, buf.Bytes())
+}
+
+// logf writes a line to l.log.
+func (l *webhookListener) logf(format string, args ...interface{}) {
+	l.write(l.log, []byte(fmt.Sprintf(format, args...)))
+}
+
+// write writes data to w in one piece.
+func (l *webhookListener) write(w io.Writer, data []byte) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	w.Write(data)
+}
+
+// forwardDelivery replays the delivery, headers included, to l.forward.
+func (l *webhookListener) forwardDelivery(r *http.Request, body []byte) error {
+	req, err := http.NewRequestWithContext(r.Context(), http.MethodPost, l.forward, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for name, values := range r.Header {
+		if strings.HasPrefix(name, "X-Agenthq-") || name == "Content-Type" || name == "User-Agent" {
+			req.Header[name] = values
+		}
+	}
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// runCommand runs l.exec with the payload on stdin and the delivery headers
+// in AGENTHQ_EVENT and AGENTHQ_DELIVERY. Its output is written to l.log once
+// it exits.
+func (l *webhookListener) runCommand(ctx context.Context, d webhookDelivery, body []byte) error {
+	var c *exec.Cmd
+	if runtime.GOOS == "windows" {
+		c = exec.CommandContext(ctx, "cmd", "/C", l.exec)
+	} else {
+		c = exec.CommandContext(ctx, "sh", "-c", l.exec)
+	}
+	var combined bytes.Buffer
+	c.Stdin = bytes.NewReader(body)
+	c.Stdout = &combined
+	c.Stderr = &combined
+	c.Env = append(os.Environ(), "AGENTHQ_EVENT="+d.Event, "AGENTHQ_DELIVERY="+d.Delivery)
+	err := c.Run()
+	l.write(l.log, combined.Bytes())
+	return err
+}
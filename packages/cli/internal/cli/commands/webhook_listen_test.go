@@ -0,0 +1,133 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Gahroot/agentHQ-cli/pkg/webhook"
+)
+
+const testPayload = `{"id":"d1","event":"post:created","org_id":"o1","timestamp":"2024-01-02T03:04:05.000Z","data":{"post_id":"p1"}}`
+
+func sign(secret, body string) string {
+	return webhook.Sign(secret, []byte(body))
+}
+
+func deliver(l *webhookListener, signature string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(testPayload))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-AgentHQ-Event", "post:created")
+	req.Header.Set("X-AgentHQ-Delivery", "d1")
+	if signature != "" {
+		req.Header.Set("X-AgentHQ-Signature", signature)
+	}
+	rec := httptest.NewRecorder()
+	l.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestWebhookListener_Signature(t *testing.T) {
+	tests := []struct {
+		name      string
+		secret    string
+		signature string
+		want      int
+	}{
+		{"valid", "s3cret", sign("s3cret", testPayload), http.StatusOK},
+		{"wrong secret", "s3cret", sign("other", testPayload), http.StatusUnauthorized},
+		{"missing", "s3cret", "", http.StatusUnauthorized},
+		{"malformed", "s3cret", "sha256=zz", http.StatusUnauthorized},
+		{"no secret configured", "", "", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out, log bytes.Buffer
+			l := &webhookListener{secret: tt.secret, out: &out, log: &log}
+			rec := deliver(l, tt.signature)
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d (log: %s)", rec.Code, tt.want, log.String())
+			}
+			if tt.want == http.StatusOK && !strings.Contains(out.String(), "post:created") {
+				t.Errorf("expected delivery to be logged, got %q", out.String())
+			}
+		})
+	}
+}
+
+func TestWebhookListener_NDJSON(t *testing.T) {
+	var out bytes.Buffer
+	l := &webhookListener{secret: "s3cret", json: true, out: &out, log: io.Discard}
+	deliver(l, sign("s3cret", testPayload))
+
+	var d map[string]interface{}
+	if err := json.Unmarshal(out.Bytes(), &d); err != nil {
+		t.Fatalf("invalid NDJSON line %q: %v", out.String(), err)
+	}
+	if d["event"] != "post:created" || d["verified"] != true || d["payload"] == nil {
+		t.Errorf("unexpected delivery %v", d)
+	}
+}
+
+func TestWebhookListener_Forward(t *testing.T) {
+	var got []byte
+	var gotEvent string
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, _ = io.ReadAll(r.Body)
+		gotEvent = r.Header.Get("X-AgentHQ-Event")
+	}))
+	defer target.Close()
+
+	l := &webhookListener{forward: target.URL, out: io.Discard, log: io.Discard, client: target.Client()}
+	if rec := deliver(l, ""); rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	if string(got) != testPayload || gotEvent != "post:created" {
+		t.Errorf("forwarded %q with event %q", got, gotEvent)
+	}
+}
+
+func TestWebhookListener_Exec(t *testing.T) {
+	var log bytes.Buffer
+	l := &webhookListener{exec: `cat; echo " $AGENTHQ_EVENT"`, out: io.Discard, log: &log}
+	if rec := deliver(l, ""); rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200 (log: %s)", rec.Code, log.String())
+	}
+	if log.String() != testPayload+" post:created\n" {
+		t.Errorf("unexpected command output %q", log.String())
+	}
+
+	l.exec = "exit 3"
+	if rec := deliver(l, ""); rec.Code != http.StatusInternalServerError {
+		t.Errorf("expected failing command to return 500, got %d", rec.Code)
+	}
+}
+
+func TestWebhookListener_ConcurrentDeliveries(t *testing.T) {
+	var out bytes.Buffer
+	l := &webhookListener{out: &out, log: io.Discard}
+
+	const n = 20
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			deliver(l, "")
+		}()
+	}
+	wg.Wait()
+
+	var pretty bytes.Buffer
+	json.Indent(&pretty, []byte(testPayload), "  ", "  ")
+	block := "  post:created      d1  unverified\n  " + pretty.String() + "\n"
+	if got := strings.Count(out.String(), block); got != n {
+		t.Errorf("expected %d whole deliveries, found %d in:\n%s", n, got, out.String())
+	}
+}
@@ -0,0 +1,66 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateWebhookEvents(t *testing.T) {
+	tests := []struct {
+		name    string
+		events  []string
+		wantErr bool
+	}{
+		{"single", []string{"post:created"}, false},
+		{"all", []string{"notification:new", "post:created", "post:reply", "task:assigned"}, false},
+		{"empty", nil, true},
+		{"unknown", []string{"post:created", "task:new"}, true},
+		{"case sensitive", []string{"Post:Created"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateWebhookEvents(tt.events)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateWebhookEvents(%v) error = %v, wantErr %v", tt.events, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGenerateWebhookSecret(t *testing.T) {
+	a, err := generateWebhookSecret()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	b, _ := generateWebhookSecret()
+	if !strings.HasPrefix(a, "whsec_") || len(a) != len("whsec_")+64 {
+		t.Errorf("unexpected secret format %q", a)
+	}
+	if a == b {
+		t.Error("expected distinct secrets")
+	}
+}
+
+func TestWithSecret(t *testing.T) {
+	tests := []struct {
+		name   string
+		data   string
+		want   string
+		wantOK bool
+	}{
+		{"object", `{"id":"wh1","url":"https://x"}`, `{"id":"wh1","url":"https://x","secret":"s3"}`, true},
+		{"empty object", `{ }`, `{"secret":"s3"}`, true},
+		{"list", `[]`, ``, false},
+		{"nothing", ``, ``, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := withSecret([]byte(tt.data), "s3")
+			if ok != tt.wantOK || string(got) != tt.want {
+				t.Errorf("withSecret(%s) = %s, %v, want %s, %v", tt.data, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
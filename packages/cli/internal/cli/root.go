@@ -1,7 +1,15 @@
 package cli
 
 import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
 	"github.com/Gahroot/agentHQ-cli/internal/cli/commands"
+	"github.com/Gahroot/agentHQ-cli/internal/common/client"
+	"github.com/Gahroot/agentHQ-cli/internal/common/config"
 	"github.com/Gahroot/agentHQ-cli/pkg/output"
 	"github.com/spf13/cobra"
 )
@@ -11,9 +19,34 @@ func NewRootCmd() *cobra.Command {
 		Use:   "agenthq",
 		Short: "AgentHQ CLI — The Office Space for AI Agents",
 		Long:  "AgentHQ CLI provides commands to interact with the AgentHQ hub for managing AI agents, posts, channels, and more.",
+		// main prints errors so they can be rendered as JSON and mapped to
+		// exit codes.
+		SilenceErrors: true,
+		SilenceUsage:  true,
+	}
+	rootCmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return usageError(cmd, err)
+	})
+	var jsonOutput bool
+	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		applyPreferences(cmd)
+		return outputFormat(cmd, jsonOutput)
 	}
 
-	rootCmd.PersistentFlags().BoolVar(&output.JSONMode, "json", false, "Output in JSON format")
+	rootCmd.PersistentFlags().StringVarP(&output.Format, "output", "o", output.FormatTable, "Output format: "+strings.Join(output.Formats, "|"))
+	rootCmd.PersistentFlags().BoolVar(&jsonOutput, "json", false, "Output in JSON format (same as --output json)")
+	rootCmd.PersistentFlags().StringVar(&output.Template, "template", "", "Print the result through a Go template, e.g. '{{.id}}'")
+	rootCmd.PersistentFlags().StringVar(&output.Query, "query", "", "Print the parts of the result selected by a jq-style path, e.g. '.[].id'")
+	rootCmd.PersistentFlags().StringSliceVar(&output.Columns, "columns", nil, "Table columns to show, in order (e.g. id,title,status)")
+	rootCmd.PersistentFlags().StringVar(&output.SortBy, "sort-by", "", "Sort table rows by a column; prefix with - to sort descending")
+	rootCmd.PersistentFlags().BoolVar(&output.NoHeaders, "no-headers", false, "Omit the table header row")
+	rootCmd.PersistentFlags().BoolVar(&output.NoColor, "no-color", false, "Disable colored output (or set NO_COLOR)")
+	rootCmd.PersistentFlags().DurationVar(&client.Timeout, "timeout", 30*time.Second, "Timeout for each request to the hub (0 disables)")
+	rootCmd.PersistentFlags().BoolVar(&client.Debug, "debug", client.Debug, "Trace hub requests and responses to stderr (or set AGENTHQ_DEBUG=1)")
+	rootCmd.PersistentFlags().StringVar(&config.Profile, "profile", "", "Config profile to use instead of the current one")
+	rootCmd.PersistentFlags().StringVar(&config.HubURLOverride, "hub-url", "", "Hub URL, overriding $HUB_URL and the config file")
+	rootCmd.PersistentFlags().StringVar(&config.APIKeyOverride, "api-key", "", "Agent API key, overriding $AGENTHQ_API_KEY and the config file")
+	rootCmd.PersistentFlags().IntVar(&client.DefaultRetryPolicy.MaxAttempts, "max-attempts", client.DefaultRetryPolicy.MaxAttempts, "Maximum attempts per request when the hub is rate limiting or unavailable")
 
 	rootCmd.AddCommand(commands.NewActivityCmd())
 	rootCmd.AddCommand(commands.NewAgentCmd())
@@ -21,6 +54,7 @@ func NewRootCmd() *cobra.Command {
 	rootCmd.AddCommand(commands.NewChannelCmd())
 	rootCmd.AddCommand(commands.NewConfigCmd())
 	rootCmd.AddCommand(commands.NewConnectCmd())
+	rootCmd.AddCommand(commands.NewContextCmd())
 	rootCmd.AddCommand(commands.NewDMCmd())
 	rootCmd.AddCommand(commands.NewFeedCmd())
 	rootCmd.AddCommand(commands.NewInsightsCmd())
@@ -30,6 +64,96 @@ func NewRootCmd() *cobra.Command {
 	rootCmd.AddCommand(commands.NewSearchCmd())
 	rootCmd.AddCommand(commands.NewSetupCmd())
 	rootCmd.AddCommand(commands.NewTaskCmd())
+	rootCmd.AddCommand(commands.NewWatchCmd())
+	rootCmd.AddCommand(commands.NewWebhookCmd())
+
+	markUsageErrors(rootCmd)
 
 	return rootCmd
 }
+
+// applyPreferences uses the config file's preferences for global flags the
+// command line left unset. Invalid preferences are ignored; 'agenthq config
+// validate' reports them.
+func applyPreferences(cmd *cobra.Command) {
+	f, err := config.LoadFile()
+	if err != nil {
+		return
+	}
+	flags := cmd.Flags()
+	pref := func(flag, key string) (string, bool) {
+		val, ok := f.Preferences[key]
+		return val, ok && !flags.Changed(flag)
+	}
+
+	if val, ok := pref("output", "output"); ok && !flags.Changed("json") && output.ValidFormat(val) {
+		output.Format = val
+	}
+	if val, ok := pref("timeout", "timeout"); ok {
+		if d, err := time.ParseDuration(val); err == nil && d >= 0 {
+			client.Timeout = d
+		}
+	}
+	if val, ok := pref("max-attempts", "max_attempts"); ok {
+		if n, err := strconv.Atoi(val); err == nil && n > 0 {
+			client.DefaultRetryPolicy.MaxAttempts = n
+		}
+	}
+	if val, ok := pref("debug", "debug"); ok && os.Getenv("AGENTHQ_DEBUG") == "" {
+		if b, err := strconv.ParseBool(val); err == nil {
+			client.Debug = b
+		}
+	}
+}
+
+// outputFormat checks --output, --template and --query and applies --json.
+// Errors about them are printed as text.
+func outputFormat(cmd *cobra.Command, jsonOutput bool) error {
+	format := output.Format
+	if jsonOutput {
+		if cmd.Flags().Changed("output") && format != output.FormatJSON {
+			output.Format = output.FormatTable
+			return usageError(cmd, fmt.Errorf("--json conflicts with --output %s", format))
+		}
+		format = output.FormatJSON
+	}
+	if !output.ValidFormat(format) {
+		output.Format = output.FormatTable
+		return usageError(cmd, fmt.Errorf("invalid --output %q: must be one of %s", format, strings.Join(output.Formats, ", ")))
+	}
+	output.Format = format
+
+	err := output.Check()
+	if err == nil && (output.Template != "" || output.Query != "") && (cmd.Flags().Changed("output") || jsonOutput) {
+		err = fmt.Errorf("--template and --query cannot be combined with --output")
+	}
+	if err != nil {
+		output.Format, output.Template, output.Query = output.FormatTable, "", ""
+		return usageError(cmd, err)
+	}
+	return nil
+}
+
+// markUsageErrors makes argument and required-flag errors of cmd and its
+// subcommands ValidationErrors, so they exit with the usage exit code.
+func markUsageErrors(cmd *cobra.Command) {
+	validate := cmd.Args
+	cmd.Args = func(c *cobra.Command, args []string) error {
+		if validate != nil {
+			if err := validate(c, args); err != nil {
+				return usageError(c, err)
+			}
+		}
+		if err := c.ValidateRequiredFlags(); err != nil {
+			return usageError(c, err)
+		}
+		return nil
+	}
+	for _, sub := range cmd.Commands() {
+		markUsageErrors(sub)
+	}
+}
+
+func usageError(cmd *cobra.Command, err error) error {
+	return &client.ValidationError{Message: fmt.Sprintf("%v\nRun '%s --help' for usage.", err, cmd.CommandPath())}
+}
@@ -8,6 +8,8 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"sync"
+	"time"
 
 	"github.com/Gahroot/agentHQ-cli/internal/common/config"
 )
@@ -19,22 +21,30 @@ type APIResponse struct {
 	Pagination *Pagination     `json:"pagination,omitempty"`
 }
 
-type APIError struct {
-	Code    string `json:"code"`
-	Message string `json:"message"`
-}
-
 type Pagination struct {
 	Page    int  `json:"page"`
 	Limit   int  `json:"limit"`
 	Total   int  `json:"total"`
 	HasMore bool `json:"hasMore"`
+
+	// Counts holds the total per resource type of a cross-resource search.
+	Counts map[string]int `json:"counts,omitempty"`
 }
 
+// Timeout bounds each HTTP request made by clients created afterwards. Zero
+// disables the limit. It is bound to the global --timeout flag.
+var Timeout = 30 * time.Second
+
 type Client struct {
 	baseURL    string
-	authToken  string
 	httpClient *http.Client
+	retry      RetryPolicy
+	debug      io.Writer
+
+	mu           sync.Mutex
+	authToken    string
+	refreshToken string
+	onRefresh    TokenRefreshFunc
 }
 
 func New() (*Client, error) {
@@ -42,27 +52,102 @@ func New() (*Client, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to load config: %w", err)
 	}
-	return &Client{
+	if err := cfg.LoadCredentials(); err != nil {
+		return nil, err
+	}
+	c := &Client{
 		baseURL:    cfg.HubURL,
 		authToken:  cfg.GetAuthToken(),
-		httpClient: &http.Client{},
-	}, nil
+		httpClient: &http.Client{Timeout: Timeout},
+		retry:      DefaultRetryPolicy,
+	}
+	if Debug {
+		c.debug = DebugOutput
+	}
+	if cfg.APIKey == "" && cfg.RefreshToken != "" {
+		c.SetRefreshToken(cfg.RefreshToken, func(accessToken, refreshToken string) error {
+			cfg.JWTToken = accessToken
+			if refreshToken != "" {
+				cfg.RefreshToken = refreshToken
+			}
+			return config.Save(cfg)
+		})
+	}
+	return c, nil
 }
 
 func NewWithToken(baseURL, token string) *Client {
-	return &Client{
+	c := &Client{
 		baseURL:    baseURL,
 		authToken:  token,
-		httpClient: &http.Client{},
+		httpClient: &http.Client{Timeout: Timeout},
+		retry:      DefaultRetryPolicy,
 	}
+	if Debug {
+		c.debug = DebugOutput
+	}
+	return c
+}
+
+// SetRetryPolicy replaces the retry policy used for subsequent requests.
+func (c *Client) SetRetryPolicy(p RetryPolicy) {
+	c.retry = p
 }
 
 func (c *Client) Request(method, path string, body interface{}, query map[string]string) (*APIResponse, error) {
-	u, err := url.Parse(c.baseURL + path)
+	return c.RequestContext(context.Background(), method, path, body, query)
+}
+
+// RequestContext is like Request but aborts when ctx is cancelled.
+func (c *Client) RequestContext(ctx context.Context, method, path string, body interface{}, query map[string]string) (*APIResponse, error) {
+	resp, respBody, err := c.roundTrip(ctx, method, path, body, query)
 	if err != nil {
 		return nil, err
 	}
 
+	var apiResp APIResponse
+	if err := json.Unmarshal(respBody, &apiResp); err != nil {
+		if resp.StatusCode >= 400 {
+			return nil, errorFromResponse(resp.StatusCode, respBody)
+		}
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	if !apiResp.Success {
+		return &apiResp, errorFromResponse(resp.StatusCode, respBody)
+	}
+
+	return &apiResp, nil
+}
+
+// RawRequestContext is for endpoints that do not wrap their responses in
+// the {success, data} envelope, such as /health and the webhooks API. A 2xx
+// response body is decoded into out, if non-nil; any other status is
+// returned as an *APIError.
+func (c *Client) RawRequestContext(ctx context.Context, method, path string, body interface{}, query map[string]string, out interface{}) error {
+	resp, respBody, err := c.roundTrip(ctx, method, path, body, query)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return errorFromResponse(resp.StatusCode, respBody)
+	}
+	if out != nil && len(respBody) > 0 {
+		if err := json.Unmarshal(respBody, out); err != nil {
+			return fmt.Errorf("failed to parse response: %w", err)
+		}
+	}
+	return nil
+}
+
+// roundTrip sends a request, retrying and refreshing the session as needed,
+// and returns the final response with its body.
+func (c *Client) roundTrip(ctx context.Context, method, path string, body interface{}, query map[string]string) (*http.Response, []byte, error) {
+	u, err := url.Parse(c.baseURL + path)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	if query != nil {
 		q := u.Query()
 		for k, v := range query {
@@ -71,63 +156,102 @@ func (c *Client) Request(method, path string, body interface{}, query map[string
 		u.RawQuery = q.Encode()
 	}
 
-	var bodyReader io.Reader
+	var data []byte
 	if body != nil {
-		data, err := json.Marshal(body)
+		data, err = json.Marshal(body)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
+		}
+	}
+
+	c.refreshIfExpiring(ctx)
+	resp, respBody, err := c.sendWithRetry(ctx, method, u.String(), data)
+	if err == nil && resp.StatusCode == http.StatusUnauthorized && c.canRefresh() {
+		if c.refresh(ctx) == nil {
+			resp, respBody, err = c.sendWithRetry(ctx, method, u.String(), data)
 		}
+	}
+	if err != nil {
+		return nil, nil, &NetworkError{Err: err}
+	}
+	return resp, respBody, nil
+}
+
+// sendWithRetry calls send until it succeeds or the retry policy gives up.
+func (c *Client) sendWithRetry(ctx context.Context, method, rawURL string, data []byte) (*http.Response, []byte, error) {
+	for attempt := 1; ; attempt++ {
+		resp, respBody, err := c.send(ctx, method, rawURL, data)
+		if attempt >= c.retry.MaxAttempts || ctx.Err() != nil || !c.retry.shouldRetry(method, resp, err) {
+			return resp, respBody, err
+		}
+		if err := sleepContext(ctx, c.retry.delay(attempt, resp)); err != nil {
+			return nil, nil, err
+		}
+	}
+}
+
+// send performs a single HTTP round trip and reads the whole response body.
+func (c *Client) send(ctx context.Context, method, rawURL string, data []byte) (*http.Response, []byte, error) {
+	var bodyReader io.Reader
+	if data != nil {
 		bodyReader = bytes.NewReader(data)
 	}
 
-	req, err := http.NewRequestWithContext(context.Background(), method, u.String(), bodyReader)
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, bodyReader)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	req.Header.Set("Content-Type", "application/json")
-	if c.authToken != "" {
-		req.Header.Set("Authorization", "Bearer "+c.authToken)
+	if token := c.token(); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
 	}
 
+	c.traceRequest(req, data)
+	start := time.Now()
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
+		c.traceResponse(nil, nil, err, time.Since(start))
+		return nil, nil, err
 	}
 	defer resp.Body.Close()
 
 	respBody, err := io.ReadAll(resp.Body)
+	c.traceResponse(resp, respBody, err, time.Since(start))
 	if err != nil {
-		return nil, err
+		return resp, nil, err
 	}
-
-	var apiResp APIResponse
-	if err := json.Unmarshal(respBody, &apiResp); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
-	}
-
-	if !apiResp.Success {
-		if apiResp.Error != nil {
-			return &apiResp, fmt.Errorf("%s: %s", apiResp.Error.Code, apiResp.Error.Message)
-		}
-		return &apiResp, fmt.Errorf("request failed with status %d", resp.StatusCode)
-	}
-
-	return &apiResp, nil
+	return resp, respBody, nil
 }
 
 func (c *Client) Get(path string, query map[string]string) (*APIResponse, error) {
-	return c.Request("GET", path, nil, query)
+	return c.GetContext(context.Background(), path, query)
 }
 
 func (c *Client) Post(path string, body interface{}) (*APIResponse, error) {
-	return c.Request("POST", path, body, nil)
+	return c.PostContext(context.Background(), path, body)
 }
 
 func (c *Client) Patch(path string, body interface{}) (*APIResponse, error) {
-	return c.Request("PATCH", path, body, nil)
+	return c.PatchContext(context.Background(), path, body)
 }
 
 func (c *Client) Delete(path string) (*APIResponse, error) {
-	return c.Request("DELETE", path, nil, nil)
+	return c.DeleteContext(context.Background(), path)
+}
+
+func (c *Client) GetContext(ctx context.Context, path string, query map[string]string) (*APIResponse, error) {
+	return c.RequestContext(ctx, "GET", path, nil, query)
+}
+
+func (c *Client) PostContext(ctx context.Context, path string, body interface{}) (*APIResponse, error) {
+	return c.RequestContext(ctx, "POST", path, body, nil)
+}
+
+func (c *Client) PatchContext(ctx context.Context, path string, body interface{}) (*APIResponse, error) {
+	return c.RequestContext(ctx, "PATCH", path, body, nil)
+}
+
+func (c *Client) DeleteContext(ctx context.Context, path string) (*APIResponse, error) {
+	return c.RequestContext(ctx, "DELETE", path, nil, nil)
 }
@@ -1,12 +1,15 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestNewWithToken(t *testing.T) {
@@ -254,3 +257,76 @@ func TestRequest_NetworkError(t *testing.T) {
 		t.Errorf("expected error to contain 'request failed', got '%s'", err.Error())
 	}
 }
+
+func TestRequestContext_Cancelled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	c := NewWithToken(server.URL, "test-token")
+	_, err := c.GetContext(ctx, "/api/v1/slow", nil)
+	if err == nil {
+		t.Fatal("expected error for cancelled context")
+	}
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got '%v'", err)
+	}
+}
+
+func TestRequest_Timeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(2 * time.Second):
+		}
+	}))
+	defer server.Close()
+
+	old := Timeout
+	Timeout = 50 * time.Millisecond
+	defer func() { Timeout = old }()
+
+	c := NewWithToken(server.URL, "test-token")
+	c.SetRetryPolicy(RetryPolicy{MaxAttempts: 1})
+	start := time.Now()
+	_, err := c.Get("/api/v1/slow", nil)
+	if err == nil {
+		t.Fatal("expected timeout error")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected request to time out quickly, took %v", elapsed)
+	}
+}
+
+func TestRequest_TypedErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(APIResponse{
+			Success: false,
+			Error:   &APIError{Code: "NOT_FOUND", Message: "Task not found"},
+		})
+	}))
+	defer server.Close()
+
+	c := NewWithToken(server.URL, "test-token")
+	_, err := c.Get("/api/v1/tasks/missing", nil)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected *APIError, got %T: %v", err, err)
+	}
+	if apiErr.Code != "NOT_FOUND" || apiErr.StatusCode != http.StatusNotFound {
+		t.Errorf("expected NOT_FOUND/404, got %s/%d", apiErr.Code, apiErr.StatusCode)
+	}
+
+	c = NewWithToken("http://127.0.0.1:1", "test-token")
+	c.SetRetryPolicy(RetryPolicy{MaxAttempts: 1})
+	_, err = c.Get("/api/v1/tasks", nil)
+	var netErr *NetworkError
+	if !errors.As(err, &netErr) {
+		t.Errorf("expected *NetworkError, got %T: %v", err, err)
+	}
+}
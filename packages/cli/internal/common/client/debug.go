@@ -0,0 +1,105 @@
+package client
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Debug makes clients created afterwards trace every request and response
+// to DebugOutput. It defaults to the AGENTHQ_DEBUG environment variable and
+// is bound to the global --debug flag.
+var Debug = debugFromEnv()
+
+// DebugOutput receives the trace when Debug is set.
+var DebugOutput io.Writer = os.Stderr
+
+// maxDebugBody is how much of a request or response body is traced.
+const maxDebugBody = 2048
+
+var (
+	apiKeyPattern = regexp.MustCompile(`ahq_[A-Za-z0-9_\-]+`)
+	secretPattern = regexp.MustCompile(`("(?:accessToken|refreshToken|password|token|apiKey|secret)"\s*:\s*)"[^"]*"`)
+)
+
+func debugFromEnv() bool {
+	v, err := strconv.ParseBool(os.Getenv("AGENTHQ_DEBUG"))
+	return err == nil && v
+}
+
+// SetDebugOutput traces requests made by c to w. A nil w disables tracing.
+func (c *Client) SetDebugOutput(w io.Writer) {
+	c.debug = w
+}
+
+func (c *Client) traceRequest(req *http.Request, data []byte) {
+	if c.debug == nil {
+		return
+	}
+	fmt.Fprintf(c.debug, "--> %s %s\n", req.Method, redactURL(req.URL))
+	if auth := req.Header.Get("Authorization"); auth != "" {
+		fmt.Fprintf(c.debug, "    Authorization: %s\n", redactAuthorization(auth))
+	}
+	if len(data) > 0 {
+		fmt.Fprintf(c.debug, "    %s\n", debugBody(data))
+	}
+}
+
+func (c *Client) traceResponse(resp *http.Response, body []byte, err error, elapsed time.Duration) {
+	if c.debug == nil {
+		return
+	}
+	elapsed = elapsed.Round(time.Millisecond)
+	if err != nil {
+		fmt.Fprintf(c.debug, "<-- error after %s: %s\n", elapsed, redact(err.Error()))
+		return
+	}
+	fmt.Fprintf(c.debug, "<-- %s (%s)\n", resp.Status, elapsed)
+	if len(body) > 0 {
+		fmt.Fprintf(c.debug, "    %s\n", debugBody(body))
+	}
+}
+
+func debugBody(body []byte) string {
+	s := redact(string(body))
+	if len(s) > maxDebugBody {
+		// Cut at the start of a rune so multi-byte characters stay whole.
+		n := maxDebugBody
+		for n > 0 && !utf8.RuneStart(s[n]) {
+			n--
+		}
+		s = fmt.Sprintf("%s... (%d bytes truncated)", s[:n], len(s)-n)
+	}
+	return s
+}
+
+func redact(s string) string {
+	s = apiKeyPattern.ReplaceAllString(s, "ahq_[REDACTED]")
+	return secretPattern.ReplaceAllString(s, `$1"[REDACTED]"`)
+}
+
+func redactAuthorization(v string) string {
+	scheme, _, found := strings.Cut(v, " ")
+	if !found {
+		return "[REDACTED]"
+	}
+	return scheme + " [REDACTED]"
+}
+
+func redactURL(u *url.URL) string {
+	q := u.Query()
+	if q.Has("token") {
+		q.Set("token", "[REDACTED]")
+		copied := *u
+		copied.RawQuery = q.Encode()
+		u = &copied
+	}
+	return redact(u.String())
+}
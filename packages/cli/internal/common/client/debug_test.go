@@ -0,0 +1,52 @@
+package client
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestDebug_TracesAndRedacts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"success":true,"data":{"apiKey":"ahq_secretkey123","accessToken":"eyJhbGciOi.payload.sig"}}`))
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	c := NewWithToken(server.URL, "ahq_mytoken")
+	c.SetDebugOutput(&buf)
+
+	if _, err := c.Post("/api/v1/auth/login", map[string]string{"email": "a@b.c", "password": "hunter2"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := buf.String()
+	for _, want := range []string{"--> POST " + server.URL + "/api/v1/auth/login", "Authorization: Bearer [REDACTED]", "<-- 201 Created", `"email":"a@b.c"`} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected trace to contain %q, got:\n%s", want, got)
+		}
+	}
+	for _, secret := range []string{"ahq_mytoken", "ahq_secretkey123", "hunter2", "eyJhbGciOi"} {
+		if strings.Contains(got, secret) {
+			t.Errorf("trace leaked %q:\n%s", secret, got)
+		}
+	}
+}
+
+func TestDebug_TruncatesBodies(t *testing.T) {
+	long := strings.Repeat("x", maxDebugBody+100)
+	got := debugBody([]byte(long))
+	if !strings.HasSuffix(got, "(100 bytes truncated)") {
+		t.Errorf("expected truncation marker, got suffix %q", got[len(got)-30:])
+	}
+
+	multi := strings.Repeat("x", maxDebugBody-1) + "é" + strings.Repeat("x", 10)
+	got = debugBody([]byte(multi))
+	if !utf8.ValidString(got) || !strings.HasSuffix(got, "(12 bytes truncated)") {
+		t.Errorf("expected truncation before the split character, got suffix %q", got[len(got)-30:])
+	}
+}
@@ -0,0 +1,125 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// APIError is an error response from the hub. Code is the hub's error code,
+// e.g. NOT_FOUND or VALIDATION_ERROR; it is empty when the hub did not send
+// one. Details lists the offending fields of a rejected request body.
+type APIError struct {
+	Code       string       `json:"code"`
+	Message    string       `json:"message"`
+	Details    []FieldError `json:"details,omitempty"`
+	StatusCode int          `json:"-"`
+}
+
+func (e *APIError) Error() string {
+	msg := e.Message
+	if e.Code != "" {
+		msg = fmt.Sprintf("%s: %s", e.Code, e.Message)
+	}
+	if len(e.Details) > 0 {
+		parts := make([]string, len(e.Details))
+		for i, d := range e.Details {
+			parts[i] = d.String()
+		}
+		msg += " (" + strings.Join(parts, "; ") + ")"
+	}
+	return msg
+}
+
+// FieldError is one validation issue reported by the hub.
+type FieldError struct {
+	Path    []interface{} `json:"path"`
+	Message string        `json:"message"`
+	Code    string        `json:"code,omitempty"`
+}
+
+func (f FieldError) String() string {
+	if len(f.Path) == 0 {
+		return f.Message
+	}
+	path := make([]string, len(f.Path))
+	for i, p := range f.Path {
+		path[i] = fmt.Sprint(p)
+	}
+	return strings.Join(path, ".") + ": " + f.Message
+}
+
+// errorFromResponse builds an APIError from a failed response. The hub
+// reports errors either in the envelope, {"error": {"code", "message"}}, or
+// bare, {"error": "message", "details": [...]}.
+func errorFromResponse(status int, body []byte) *APIError {
+	apiErr := &APIError{StatusCode: status}
+
+	var parsed struct {
+		Error   json.RawMessage `json:"error"`
+		Details []FieldError    `json:"details"`
+	}
+	if json.Unmarshal(body, &parsed) == nil && len(parsed.Error) > 0 {
+		var nested APIError
+		var message string
+		if json.Unmarshal(parsed.Error, &nested) == nil {
+			apiErr.Code, apiErr.Message, apiErr.Details = nested.Code, nested.Message, nested.Details
+		} else if json.Unmarshal(parsed.Error, &message) == nil {
+			apiErr.Message = message
+		}
+		if len(parsed.Details) > 0 {
+			apiErr.Details = parsed.Details
+		}
+	}
+
+	if apiErr.Message == "" {
+		apiErr.Message = fmt.Sprintf("request failed with status %d", status)
+	}
+	if apiErr.Code == "" && status >= 400 {
+		if len(apiErr.Details) > 0 {
+			apiErr.Code = "VALIDATION_ERROR"
+		} else if text := http.StatusText(status); text != "" {
+			apiErr.Code = strings.ToUpper(strings.ReplaceAll(text, " ", "_"))
+		}
+	}
+	return apiErr
+}
+
+// IsAuth reports whether the hub rejected the credentials or denied access.
+func (e *APIError) IsAuth() bool {
+	return e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden
+}
+
+// NetworkError means the hub could not be reached or did not answer in time.
+type NetworkError struct {
+	Err error
+}
+
+func (e *NetworkError) Error() string {
+	return fmt.Sprintf("request failed: %v", e.Err)
+}
+
+func (e *NetworkError) Unwrap() error {
+	return e.Err
+}
+
+// ValidationError reports invalid input caught before anything was sent to
+// the hub.
+type ValidationError struct {
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+// AuthError reports missing credentials, e.g. a command that needs a login
+// run before one.
+type AuthError struct {
+	Message string
+}
+
+func (e *AuthError) Error() string {
+	return e.Message
+}
@@ -0,0 +1,91 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestErrorFromResponse(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		body     string
+		wantCode string
+		wantErr  string
+	}{
+		{
+			"envelope",
+			404, `{"success":false,"error":{"code":"NOT_FOUND","message":"Task not found"}}`,
+			"NOT_FOUND", "NOT_FOUND: Task not found",
+		},
+		{
+			"bare string",
+			404, `{"error":"Webhook not found"}`,
+			"NOT_FOUND", "NOT_FOUND: Webhook not found",
+		},
+		{
+			"zod details",
+			400, `{"error":"Validation failed","details":[{"code":"invalid_string","path":["url"],"message":"Invalid URL format"},{"path":["events",0],"message":"Invalid enum value"}]}`,
+			"VALIDATION_ERROR", "VALIDATION_ERROR: Validation failed (url: Invalid URL format; events.0: Invalid enum value)",
+		},
+		{
+			"not JSON",
+			502, `<html>Bad Gateway</html>`,
+			"BAD_GATEWAY", "BAD_GATEWAY: request failed with status 502",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := errorFromResponse(tt.status, []byte(tt.body))
+			if err.Code != tt.wantCode {
+				t.Errorf("Code = %q, want %q", err.Code, tt.wantCode)
+			}
+			if err.Error() != tt.wantErr {
+				t.Errorf("Error() = %q, want %q", err.Error(), tt.wantErr)
+			}
+			if err.StatusCode != tt.status {
+				t.Errorf("StatusCode = %d, want %d", err.StatusCode, tt.status)
+			}
+		})
+	}
+}
+
+func TestRawRequestContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/webhooks":
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id":"wh-1","url":"https://example.com/hook","events":["post:created"],"active":true}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":"Validation failed","details":[{"path":["url"],"message":"Invalid URL format"}]}`))
+		}
+	}))
+	defer server.Close()
+
+	c := NewWithToken(server.URL, "test-token")
+
+	var webhook struct {
+		ID     string   `json:"id"`
+		Events []string `json:"events"`
+	}
+	if err := c.RawRequestContext(context.Background(), "POST", "/api/v1/webhooks", map[string]string{"url": "x"}, nil, &webhook); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if webhook.ID != "wh-1" || len(webhook.Events) != 1 {
+		t.Errorf("unexpected webhook %+v", webhook)
+	}
+
+	err := c.RawRequestContext(context.Background(), "POST", "/api/v1/other", nil, nil, nil)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected *APIError, got %T: %v", err, err)
+	}
+	if len(apiErr.Details) != 1 || apiErr.Details[0].String() != "url: Invalid URL format" {
+		t.Errorf("expected url detail, got %+v", apiErr.Details)
+	}
+}
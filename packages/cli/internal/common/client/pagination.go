@@ -0,0 +1,80 @@
+package client
+
+import "context"
+
+// maxPages bounds an iteration so a hub that keeps reporting hasMore cannot
+// loop forever.
+const maxPages = 10000
+
+// PageFunc fetches one page of a paginated list endpoint.
+type PageFunc[T any] func(ctx context.Context, page, limit int) ([]T, *Pagination, error)
+
+// Iterator walks the pages of a list endpoint one request at a time.
+//
+//	it := client.NewIterator(fetch, 1, 100)
+//	for it.Next(ctx) {
+//		for _, item := range it.Items() { ... }
+//	}
+//	if err := it.Err(); err != nil { ... }
+type Iterator[T any] struct {
+	fetch      PageFunc[T]
+	page       int
+	limit      int
+	items      []T
+	pagination *Pagination
+	err        error
+	done       bool
+}
+
+// NewIterator returns an iterator starting at page. A page or limit of zero
+// uses the hub's defaults.
+func NewIterator[T any](fetch PageFunc[T], page, limit int) *Iterator[T] {
+	if page < 1 {
+		page = 1
+	}
+	return &Iterator[T]{fetch: fetch, page: page, limit: limit}
+}
+
+// Next fetches the next page, reporting whether one was retrieved.
+func (it *Iterator[T]) Next(ctx context.Context) bool {
+	if it.done || it.err != nil {
+		return false
+	}
+	items, pagination, err := it.fetch(ctx, it.page, it.limit)
+	if err != nil {
+		it.err = err
+		return false
+	}
+	it.items = items
+	it.pagination = pagination
+	it.page++
+	if pagination == nil || !pagination.HasMore || len(items) == 0 || it.page > maxPages {
+		it.done = true
+	}
+	return true
+}
+
+// Items returns the items of the current page.
+func (it *Iterator[T]) Items() []T {
+	return it.items
+}
+
+// Pagination returns the pagination block of the current page.
+func (it *Iterator[T]) Pagination() *Pagination {
+	return it.pagination
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// CollectAll fetches every remaining page and returns the concatenated items
+// along with the pagination block of the last page.
+func CollectAll[T any](ctx context.Context, it *Iterator[T]) ([]T, *Pagination, error) {
+	var all []T
+	for it.Next(ctx) {
+		all = append(all, it.Items()...)
+	}
+	return all, it.Pagination(), it.Err()
+}
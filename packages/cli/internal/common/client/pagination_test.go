@@ -0,0 +1,71 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestIterator_AllPages(t *testing.T) {
+	var requested []int
+	fetch := func(ctx context.Context, page, limit int) ([]int, *Pagination, error) {
+		requested = append(requested, page)
+		if limit != 2 {
+			t.Errorf("expected limit=2, got %d", limit)
+		}
+		items := []int{page*10 + 1, page*10 + 2}
+		if page == 3 {
+			items = items[:1]
+		}
+		return items, &Pagination{Page: page, Limit: limit, Total: 5, HasMore: page < 3}, nil
+	}
+
+	items, pagination, err := CollectAll(context.Background(), NewIterator(fetch, 0, 2))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(items) != 5 {
+		t.Errorf("expected 5 items, got %v", items)
+	}
+	if len(requested) != 3 || requested[0] != 1 {
+		t.Errorf("expected pages 1..3, got %v", requested)
+	}
+	if pagination.Page != 3 {
+		t.Errorf("expected last pagination page=3, got %d", pagination.Page)
+	}
+}
+
+func TestIterator_StopsOnError(t *testing.T) {
+	boom := errors.New("boom")
+	fetch := func(ctx context.Context, page, limit int) ([]string, *Pagination, error) {
+		if page == 2 {
+			return nil, nil, boom
+		}
+		return []string{"a"}, &Pagination{Page: page, HasMore: true}, nil
+	}
+
+	it := NewIterator(fetch, 1, 0)
+	var pages int
+	for it.Next(context.Background()) {
+		pages++
+	}
+	if pages != 1 {
+		t.Errorf("expected 1 page before error, got %d", pages)
+	}
+	if !errors.Is(it.Err(), boom) {
+		t.Errorf("expected boom, got %v", it.Err())
+	}
+}
+
+func TestIterator_NoPagination(t *testing.T) {
+	var calls int
+	fetch := func(ctx context.Context, page, limit int) ([]string, *Pagination, error) {
+		calls++
+		return []string{"a", "b"}, nil, nil
+	}
+
+	items, _, err := CollectAll(context.Background(), NewIterator(fetch, 1, 0))
+	if err != nil || len(items) != 2 || calls != 1 {
+		t.Errorf("expected a single page of 2 items, got %v (calls=%d, err=%v)", items, calls, err)
+	}
+}
@@ -0,0 +1,116 @@
+package client
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// refreshSkew renews access tokens slightly before they expire so a request
+// does not race the expiry on its way to the hub.
+const refreshSkew = 30 * time.Second
+
+// TokenRefreshFunc is called after the client obtains a new access token.
+// refreshToken is empty when the hub did not rotate it.
+type TokenRefreshFunc func(accessToken, refreshToken string) error
+
+// SetRefreshToken enables transparent renewal of an expired user JWT.
+// onRefresh, if non-nil, is called with the new tokens so they can be
+// persisted.
+func (c *Client) SetRefreshToken(refreshToken string, onRefresh TokenRefreshFunc) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.refreshToken = refreshToken
+	c.onRefresh = onRefresh
+}
+
+func (c *Client) token() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.authToken
+}
+
+// canRefresh reports whether the client holds a user JWT it can renew. API
+// keys never expire and are not refreshed.
+func (c *Client) canRefresh() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.refreshToken != "" && !strings.HasPrefix(c.authToken, "ahq_")
+}
+
+func (c *Client) refreshIfExpiring(ctx context.Context) {
+	if !c.canRefresh() {
+		return
+	}
+	exp, ok := jwtExpiry(c.token())
+	if !ok || time.Until(exp) > refreshSkew {
+		return
+	}
+	// A failed refresh is not fatal here: the request goes out with the old
+	// token and the hub's 401 is reported as usual.
+	_ = c.refresh(ctx)
+}
+
+// refresh exchanges the refresh token for a new access token.
+func (c *Client) refresh(ctx context.Context) error {
+	c.mu.Lock()
+	refreshToken := c.refreshToken
+	c.mu.Unlock()
+
+	data, err := json.Marshal(map[string]string{"refreshToken": refreshToken})
+	if err != nil {
+		return err
+	}
+	resp, respBody, err := c.send(ctx, http.MethodPost, c.baseURL+"/api/v1/auth/refresh", data)
+	if err != nil {
+		return fmt.Errorf("token refresh failed: %w", err)
+	}
+
+	var apiResp APIResponse
+	if err := json.Unmarshal(respBody, &apiResp); err != nil || !apiResp.Success {
+		return fmt.Errorf("token refresh failed with status %d", resp.StatusCode)
+	}
+	var tokens struct {
+		AccessToken  string `json:"accessToken"`
+		RefreshToken string `json:"refreshToken"`
+	}
+	if err := json.Unmarshal(apiResp.Data, &tokens); err != nil || tokens.AccessToken == "" {
+		return fmt.Errorf("token refresh returned no access token")
+	}
+
+	c.mu.Lock()
+	c.authToken = tokens.AccessToken
+	if tokens.RefreshToken != "" {
+		c.refreshToken = tokens.RefreshToken
+	}
+	onRefresh := c.onRefresh
+	c.mu.Unlock()
+
+	if onRefresh != nil {
+		return onRefresh(tokens.AccessToken, tokens.RefreshToken)
+	}
+	return nil
+}
+
+// jwtExpiry decodes the exp claim of a JWT without verifying its signature.
+func jwtExpiry(token string) (time.Time, bool) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}, false
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return time.Time{}, false
+	}
+	var claims struct {
+		Exp int64 `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Exp == 0 {
+		return time.Time{}, false
+	}
+	return time.Unix(claims.Exp, 0), true
+}
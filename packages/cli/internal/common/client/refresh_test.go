@@ -0,0 +1,122 @@
+package client
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func makeJWT(exp time.Time) string {
+	payload, _ := json.Marshal(map[string]int64{"exp": exp.Unix()})
+	return "eyJhbGciOiJIUzI1NiJ9." + base64.RawURLEncoding.EncodeToString(payload) + ".sig"
+}
+
+func TestJWTExpiry(t *testing.T) {
+	exp := time.Unix(1700000000, 0)
+	got, ok := jwtExpiry(makeJWT(exp))
+	if !ok || !got.Equal(exp) {
+		t.Errorf("expected %v, got %v (ok=%v)", exp, got, ok)
+	}
+
+	for _, token := range []string{"", "ahq_abc", "a.b.c", "a.!!.c"} {
+		if _, ok := jwtExpiry(token); ok {
+			t.Errorf("expected %q to have no expiry", token)
+		}
+	}
+}
+
+func TestRefresh_On401(t *testing.T) {
+	fresh := makeJWT(time.Now().Add(time.Hour))
+	var refreshes int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/auth/refresh" {
+			refreshes++
+			body, _ := io.ReadAll(r.Body)
+			if string(body) != `{"refreshToken":"refresh-1"}` {
+				t.Errorf("unexpected refresh body %s", body)
+			}
+			fmt.Fprintf(w, `{"success":true,"data":{"accessToken":%q}}`, fresh)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer "+fresh {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"success":false,"error":{"code":"UNAUTHORIZED","message":"Token expired"}}`))
+			return
+		}
+		w.Write([]byte(`{"success":true,"data":{}}`))
+	}))
+	defer server.Close()
+
+	c := NewWithToken(server.URL, "stale")
+	var saved string
+	c.SetRefreshToken("refresh-1", func(accessToken, refreshToken string) error {
+		saved = accessToken
+		return nil
+	})
+
+	if _, err := c.Get("/api/v1/tasks", nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if refreshes != 1 {
+		t.Errorf("expected 1 refresh, got %d", refreshes)
+	}
+	if saved != fresh {
+		t.Errorf("expected refreshed token to be persisted")
+	}
+}
+
+func TestRefresh_ExpiredTokenRenewedBeforeRequest(t *testing.T) {
+	fresh := makeJWT(time.Now().Add(time.Hour))
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path == "/api/v1/auth/refresh" {
+			fmt.Fprintf(w, `{"success":true,"data":{"accessToken":%q,"refreshToken":"refresh-2"}}`, fresh)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer "+fresh {
+			t.Errorf("expected request with refreshed token")
+		}
+		w.Write([]byte(`{"success":true,"data":{}}`))
+	}))
+	defer server.Close()
+
+	c := NewWithToken(server.URL, makeJWT(time.Now().Add(-time.Minute)))
+	var rotated string
+	c.SetRefreshToken("refresh-1", func(accessToken, refreshToken string) error {
+		rotated = refreshToken
+		return nil
+	})
+
+	if _, err := c.Get("/api/v1/tasks", nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("expected refresh + request, got %d requests", requests)
+	}
+	if rotated != "refresh-2" {
+		t.Errorf("expected rotated refresh token, got %q", rotated)
+	}
+}
+
+func TestRefresh_APIKeyNeverRefreshed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/auth/refresh" {
+			t.Error("API keys must not be refreshed")
+		}
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"success":false,"error":{"code":"UNAUTHORIZED","message":"Invalid API key"}}`))
+	}))
+	defer server.Close()
+
+	c := NewWithToken(server.URL, "ahq_key")
+	c.SetRefreshToken("refresh-1", nil)
+	if _, err := c.Get("/api/v1/tasks", nil); err == nil {
+		t.Fatal("expected error")
+	}
+}
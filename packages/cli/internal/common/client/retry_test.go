@@ -0,0 +1,115 @@
+package client
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func fastRetryClient(url string) *Client {
+	c := NewWithToken(url, "test-token")
+	c.SetRetryPolicy(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond})
+	return c
+}
+
+func TestRetry_RateLimitedThenSuccess(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			json.NewEncoder(w).Encode(APIResponse{Success: false, Error: &APIError{Code: "RATE_LIMITED", Message: "slow down"}})
+			return
+		}
+		json.NewEncoder(w).Encode(APIResponse{Success: true})
+	}))
+	defer server.Close()
+
+	c := fastRetryClient(server.URL)
+	if _, err := c.Post("/api/v1/posts", map[string]string{"content": "hi"}); err != nil {
+		t.Fatalf("expected POST to succeed after 429, got: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("expected 2 attempts, got %d", calls)
+	}
+}
+
+func TestRetry_ServerErrorIdempotentOnly(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(APIResponse{Success: false})
+	}))
+	defer server.Close()
+
+	c := fastRetryClient(server.URL)
+	if _, err := c.Get("/api/v1/items", nil); err == nil {
+		t.Fatal("expected error after exhausting retries")
+	}
+	if calls != 3 {
+		t.Errorf("expected GET to be attempted 3 times, got %d", calls)
+	}
+
+	atomic.StoreInt32(&calls, 0)
+	if _, err := c.Post("/api/v1/items", nil); err == nil {
+		t.Fatal("expected error for failing POST")
+	}
+	if calls != 1 {
+		t.Errorf("expected POST not to be retried on 5xx, got %d attempts", calls)
+	}
+}
+
+func TestRetry_Disabled(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	c := NewWithToken(server.URL, "test-token")
+	c.SetRetryPolicy(RetryPolicy{MaxAttempts: 1})
+	c.Get("/api/v1/items", nil)
+	if calls != 1 {
+		t.Errorf("expected a single attempt, got %d", calls)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		value string
+		want  time.Duration
+		ok    bool
+	}{
+		{"Empty", "", 0, false},
+		{"Seconds", "7", 7 * time.Second, true},
+		{"Negative", "-1", 0, false},
+		{"HTTPDate", now.Add(90 * time.Second).Format(http.TimeFormat), 90 * time.Second, true},
+		{"PastDate", now.Add(-time.Minute).Format(http.TimeFormat), 0, true},
+		{"Garbage", "soon", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseRetryAfter(tt.value, now)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("parseRetryAfter(%q) = (%v, %v), want (%v, %v)", tt.value, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestRetryPolicy_DelayBounded(t *testing.T) {
+	p := RetryPolicy{MaxAttempts: 10, BaseDelay: 10 * time.Millisecond, MaxDelay: 40 * time.Millisecond}
+	for attempt := 1; attempt < 10; attempt++ {
+		if d := p.delay(attempt, nil); d < 0 || d > p.MaxDelay {
+			t.Errorf("delay(%d) = %v, want within [0, %v]", attempt, d, p.MaxDelay)
+		}
+	}
+}
@@ -2,16 +2,77 @@ package config
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 )
 
+// DefaultProfile is used when no profile has been selected.
+const DefaultProfile = "default"
+
+const defaultHubURL = "http://localhost:3000"
+
+// Profile selects the profile Load and Save use instead of the file's
+// current profile. It is bound to the global --profile flag.
+var Profile string
+
+// HubURLOverride and APIKeyOverride take precedence over the environment and
+// the config file. They are bound to the global --hub-url and --api-key flags.
+var (
+	HubURLOverride string
+	APIKeyOverride string
+)
+
+// Environment variables read by Load, matching the output of auth export.
+const (
+	EnvHubURL  = "HUB_URL"
+	EnvAPIKey  = "AGENTHQ_API_KEY"
+	EnvAgentID = "AGENTHQ_AGENT_ID"
+	EnvOrgID   = "AGENTHQ_ORG_ID"
+)
+
+// Config is the settings of one profile.
 type Config struct {
-	HubURL   string `json:"hub_url"`
-	APIKey   string `json:"api_key"`
-	JWTToken string `json:"jwt_token"`
-	OrgID    string `json:"org_id"`
-	AgentID  string `json:"agent_id"`
+	HubURL       string `json:"hub_url"`
+	APIKey       string `json:"api_key"`
+	JWTToken     string `json:"jwt_token"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	OrgID        string `json:"org_id"`
+	AgentID      string `json:"agent_id"`
+
+	// Profile is the name of the profile the settings belong to.
+	Profile string `json:"-"`
+
+	// Sources records where Load found each value, keyed by JSON name:
+	// "default", "profile:<name>", "<store>:<name>", "project:<path>",
+	// "env:<VAR>" or "flag:--<name>".
+	Sources map[string]string `json:"-"`
+
+	// stored holds the profile's values and applied the defaults and
+	// overrides Load replaced them with, so Save does not write environment
+	// or flag values into the file.
+	stored  *Config
+	applied map[string]string
+
+	// store keeps the profile's credentials when they are not in
+	// config.json. They are read by LoadCredentials, not Load, so commands
+	// that need no secret work without the store's passphrase.
+	store       CredentialStore
+	storeName   string
+	credsLoaded bool
+}
+
+// File is the on-disk config: named profiles, the one in use, where their
+// credentials are kept and user preferences.
+type File struct {
+	Version          int                `json:"version"`
+	CurrentProfile   string             `json:"current_profile"`
+	CredentialStore  string             `json:"credential_store,omitempty"`
+	CredentialHelper string             `json:"credential_helper,omitempty"`
+	KeyFile          string             `json:"credential_key_file,omitempty"`
+	Preferences      map[string]string  `json:"preferences,omitempty"`
+	Profiles         map[string]*Config `json:"profiles"`
 }
 
 func configDir() string {
@@ -23,34 +84,398 @@ func configPath() string {
 	return filepath.Join(configDir(), "config.json")
 }
 
-func Load() (*Config, error) {
+// ConfigPath returns the path of the user config file.
+func ConfigPath() string {
+	return configPath()
+}
+
+// LoadFile reads the config file. Files written by older versions are
+// migrated to the current format and saved, keeping the original as
+// config.json.v<N>.bak.
+func LoadFile() (*File, error) {
+	f := &File{Version: CurrentVersion, Profiles: map[string]*Config{}}
 	data, err := os.ReadFile(configPath())
 	if err != nil {
 		if os.IsNotExist(err) {
-			return &Config{HubURL: "http://localhost:3000"}, nil
+			return f, nil
 		}
 		return nil, err
 	}
-	var cfg Config
-	if err := json.Unmarshal(data, &cfg); err != nil {
+
+	migrated, from, err := migrate(data)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", configPath(), err)
+	}
+	if err := json.Unmarshal(migrated, f); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", configPath(), err)
+	}
+	if f.Profiles == nil {
+		f.Profiles = map[string]*Config{}
+	}
+	for name, p := range f.Profiles {
+		if p == nil {
+			f.Profiles[name] = &Config{}
+		}
+	}
+
+	if from < CurrentVersion {
+		// Best effort: a read-only config still loads.
+		backup := fmt.Sprintf("%s.v%d.bak", configPath(), from)
+		if err := os.WriteFile(backup, data, 0600); err == nil {
+			SaveFile(f)
+		}
+	}
+	return f, nil
+}
+
+// SaveFile writes f to the config file, readable only by the user.
+func SaveFile(f *File) error {
+	f.Version = CurrentVersion
+	dir := configDir()
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(configPath(), data, 0600)
+}
+
+// Active returns the name of the profile in use: the one selected with
+// --profile, then the project file's, then the current profile.
+func (f *File) Active() string {
+	if Profile != "" {
+		return Profile
+	}
+	if p, err := LoadProject(); err == nil && p.Profile != "" {
+		return p.Profile
+	}
+	if f.CurrentProfile != "" {
+		return f.CurrentProfile
+	}
+	return DefaultProfile
+}
+
+// Names returns the profile names in sorted order.
+func (f *File) Names() []string {
+	names := make([]string, 0, len(f.Profiles))
+	for name := range f.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ProfileError reports a profile that is missing, or already exists when
+// Exists is set.
+type ProfileError struct {
+	Name   string
+	Exists bool
+}
+
+func (e *ProfileError) Error() string {
+	if e.Exists {
+		return fmt.Sprintf("profile %q already exists", e.Name)
+	}
+	return fmt.Sprintf("profile %q does not exist", e.Name)
+}
+
+// Use makes name the current profile.
+func (f *File) Use(name string) error {
+	if _, ok := f.Profiles[name]; !ok {
+		return &ProfileError{Name: name}
+	}
+	f.CurrentProfile = name
+	return nil
+}
+
+// Rename renames a profile, following it if it is the current one.
+func (f *File) Rename(from, to string) error {
+	p, ok := f.Profiles[from]
+	if !ok {
+		return &ProfileError{Name: from}
+	}
+	if _, exists := f.Profiles[to]; exists {
+		return &ProfileError{Name: to, Exists: true}
+	}
+	delete(f.Profiles, from)
+	f.Profiles[to] = p
+	if f.CurrentProfile == from {
+		f.CurrentProfile = to
+	}
+	return nil
+}
+
+// Delete removes a profile. Deleting the current profile leaves no profile
+// selected.
+func (f *File) Delete(name string) error {
+	if _, ok := f.Profiles[name]; !ok {
+		return &ProfileError{Name: name}
+	}
+	delete(f.Profiles, name)
+	if f.CurrentProfile == name {
+		f.CurrentProfile = ""
+	}
+	return nil
+}
+
+// Load returns the settings of the active profile with overrides applied.
+// Precedence, highest first: --hub-url/--api-key flags, environment
+// variables, the project file, the profile, then defaults. A profile that
+// does not exist yet loads empty, so logging in with --profile creates it.
+func Load() (*Config, error) {
+	f, err := LoadFile()
+	if err != nil {
 		return nil, err
 	}
+	project, err := LoadProject()
+	if err != nil {
+		return nil, err
+	}
+	name := f.Active()
+	cfg := Config{}
+	if p, ok := f.Profiles[name]; ok {
+		cfg = *p
+	}
+	stored := cfg
+	cfg.Profile = name
+	cfg.stored = &stored
+
+	cfg.Sources = map[string]string{}
+	for key, val := range cfg.fields() {
+		if *val != "" {
+			cfg.Sources[key] = "profile:" + name
+		}
+	}
+	if storeName := f.StoreName(); storeName != StorePlaintext {
+		store, err := f.Store()
+		if err != nil {
+			return nil, err
+		}
+		cfg.store, cfg.storeName = store, storeName
+	}
+	cfg.applied = map[string]string{}
+	override := func(key, val, source string) {
+		*cfg.fields()[key] = val
+		cfg.Sources[key] = source
+		cfg.applied[key] = val
+	}
+
 	if cfg.HubURL == "" {
-		cfg.HubURL = "http://localhost:3000"
+		override("hub_url", defaultHubURL, "default")
+	}
+
+	projectValues := map[string]string{
+		"org_id":   project.OrgID,
+		"agent_id": project.AgentID,
+	}
+	for key, val := range projectValues {
+		if val != "" {
+			override(key, val, "project:"+project.Path)
+		}
+	}
+
+	envs := map[string]string{
+		"hub_url":  EnvHubURL,
+		"api_key":  EnvAPIKey,
+		"agent_id": EnvAgentID,
+		"org_id":   EnvOrgID,
+	}
+	for key, env := range envs {
+		if v := os.Getenv(env); v != "" {
+			override(key, v, "env:"+env)
+		}
+	}
+	if HubURLOverride != "" {
+		override("hub_url", HubURLOverride, "flag:--hub-url")
+	}
+	if APIKeyOverride != "" {
+		override("api_key", APIKeyOverride, "flag:--api-key")
 	}
 	return &cfg, nil
 }
 
+// LoadCredentials reads the profile's credentials from the credential store,
+// which may need its passphrase. Credentials in config.json, the environment
+// or flags are there after Load; commands that authenticate must call this
+// before using the others. Values overridden by the environment or flags
+// are kept.
+func (c *Config) LoadCredentials() error {
+	if c.store == nil || c.credsLoaded {
+		return nil
+	}
+	creds, err := c.store.Get(c.Profile)
+	if err != nil {
+		return fmt.Errorf("reading credentials: %w", err)
+	}
+	c.credsLoaded = true
+	// Credentials still in config.json are kept for any the store lacks.
+	stored := c.stored.fields()
+	for key, val := range map[string]string{"api_key": creds.APIKey, "jwt_token": creds.JWTToken, "refresh_token": creds.RefreshToken} {
+		if val == "" {
+			continue
+		}
+		*stored[key] = val
+		if _, overridden := c.applied[key]; overridden {
+			continue
+		}
+		*c.fields()[key] = val
+		c.Sources[key] = c.storeName + ":" + c.Profile
+	}
+	return nil
+}
+
+// fields maps JSON names to the settings of c.
+func (c *Config) fields() map[string]*string {
+	return map[string]*string{
+		"hub_url":       &c.HubURL,
+		"api_key":       &c.APIKey,
+		"jwt_token":     &c.JWTToken,
+		"refresh_token": &c.RefreshToken,
+		"org_id":        &c.OrgID,
+		"agent_id":      &c.AgentID,
+	}
+}
+
+// Save stores cfg as its profile, or the active profile if cfg.Profile is
+// empty, leaving other profiles untouched. Values that came from the
+// environment or flags and were not changed keep their stored value. If cfg
+// was loaded without LoadCredentials, credentials it sets are merged over the
+// stored ones, so clearing one needs LoadCredentials first. The first
+// profile saved becomes the current one.
 func Save(cfg *Config) error {
-	dir := configDir()
-	if err := os.MkdirAll(dir, 0700); err != nil {
+	f, err := LoadFile()
+	if err != nil {
 		return err
 	}
-	data, err := json.MarshalIndent(cfg, "", "  ")
+	name := cfg.Profile
+	if name == "" {
+		name = f.Active()
+	}
+	stored := Config{HubURL: cfg.HubURL, APIKey: cfg.APIKey, JWTToken: cfg.JWTToken,
+		RefreshToken: cfg.RefreshToken, OrgID: cfg.OrgID, AgentID: cfg.AgentID}
+	if cfg.stored != nil {
+		orig := cfg.stored.fields()
+		for key, val := range stored.fields() {
+			if applied, ok := cfg.applied[key]; ok && *val == applied {
+				*val = *orig[key]
+			}
+		}
+	}
+
+	// The store in use is recorded explicitly, and credentials left in
+	// config.json by older versions move into it.
+	storeName := f.StoreName()
+	f.CredentialStore = storeName
+	store, err := f.Store()
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(configPath(), data, 0600)
+	plaintext := f.takePlaintext()
+	delete(plaintext, name)
+	for other, creds := range plaintext {
+		old, err := store.Get(other)
+		if err != nil {
+			return fmt.Errorf("saving credentials: %w", err)
+		}
+		creds.fill(old)
+		if err := store.Store(other, creds); err != nil {
+			return fmt.Errorf("saving credentials: %w", err)
+		}
+	}
+
+	f.Profiles[name] = &stored
+	creds := &Credentials{APIKey: stored.APIKey, JWTToken: stored.JWTToken, RefreshToken: stored.RefreshToken}
+	if storeName != StorePlaintext {
+		stored.APIKey, stored.JWTToken, stored.RefreshToken = "", "", ""
+	}
+	if cfg.store != nil && !cfg.credsLoaded {
+		// Credentials set since Load replace the stored ones; the rest, never
+		// read, are kept.
+		if creds.empty() {
+			return saveFile(f, name)
+		}
+		old, err := store.Get(name)
+		if err != nil {
+			return fmt.Errorf("saving credentials: %w", err)
+		}
+		creds.fill(old)
+	}
+	if err := store.Store(name, creds); err != nil {
+		return fmt.Errorf("saving credentials: %w", err)
+	}
+	return saveFile(f, name)
+}
+
+// saveFile writes f after saving profile name, which becomes the current
+// profile if there is none.
+func saveFile(f *File, name string) error {
+	if f.CurrentProfile == "" {
+		f.CurrentProfile = name
+	}
+	return SaveFile(f)
+}
+
+// UseProfile makes name the current profile.
+func UseProfile(name string) error {
+	f, err := LoadFile()
+	if err != nil {
+		return err
+	}
+	if err := f.Use(name); err != nil {
+		return err
+	}
+	return SaveFile(f)
+}
+
+// RenameProfile renames a profile along with its stored credentials.
+func RenameProfile(from, to string) error {
+	f, err := LoadFile()
+	if err != nil {
+		return err
+	}
+	if err := f.Rename(from, to); err != nil {
+		return err
+	}
+	if f.StoreName() != StorePlaintext {
+		store, err := f.Store()
+		if err != nil {
+			return err
+		}
+		creds, err := store.Get(from)
+		if err != nil {
+			return err
+		}
+		if err := store.Store(to, creds); err != nil {
+			return err
+		}
+		if err := store.Erase(from); err != nil {
+			return err
+		}
+	}
+	return SaveFile(f)
+}
+
+// DeleteProfile removes a profile and erases its stored credentials.
+func DeleteProfile(name string) error {
+	f, err := LoadFile()
+	if err != nil {
+		return err
+	}
+	if err := f.Delete(name); err != nil {
+		return err
+	}
+	if f.StoreName() != StorePlaintext {
+		store, err := f.Store()
+		if err != nil {
+			return err
+		}
+		if err := store.Erase(name); err != nil {
+			return err
+		}
+	}
+	return SaveFile(f)
 }
 
 func (c *Config) GetAuthToken() string {
@@ -136,16 +136,26 @@ func TestSave_WritesJSON(t *testing.T) {
 		t.Fatalf("failed to read saved config file: %v", err)
 	}
 
-	var loaded Config
-	if err := json.Unmarshal(data, &loaded); err != nil {
+	var file File
+	if err := json.Unmarshal(data, &file); err != nil {
 		t.Fatalf("saved config is not valid JSON: %v", err)
 	}
+	if file.CurrentProfile != DefaultProfile {
+		t.Errorf("expected current_profile=%q, got %q", DefaultProfile, file.CurrentProfile)
+	}
+	loaded := file.Profiles[DefaultProfile]
+	if loaded == nil {
+		t.Fatalf("expected profile %q in saved config, got %s", DefaultProfile, data)
+	}
 
 	if loaded.HubURL != "https://hub.example.com" {
 		t.Errorf("expected HubURL='https://hub.example.com', got '%s'", loaded.HubURL)
 	}
-	if loaded.APIKey != "ahq_mykey" {
-		t.Errorf("expected APIKey='ahq_mykey', got '%s'", loaded.APIKey)
+	if loaded.APIKey != "" || loaded.JWTToken != "" {
+		t.Errorf("expected credentials to be kept out of config.json, got %s", data)
+	}
+	if file.CredentialStore != StoreEncryptedFile {
+		t.Errorf("expected credential_store=%q, got %q", StoreEncryptedFile, file.CredentialStore)
 	}
 	if loaded.OrgID != "org-abc" {
 		t.Errorf("expected OrgID='org-abc', got '%s'", loaded.OrgID)
@@ -172,6 +182,9 @@ func TestSaveAndLoad_RoundTrip(t *testing.T) {
 	if err != nil {
 		t.Fatalf("unexpected error loading config: %v", err)
 	}
+	if err := loaded.LoadCredentials(); err != nil {
+		t.Fatalf("unexpected error loading credentials: %v", err)
+	}
 
 	if loaded.HubURL != original.HubURL {
 		t.Errorf("HubURL mismatch: got '%s', want '%s'", loaded.HubURL, original.HubURL)
@@ -232,3 +245,167 @@ func TestGetAuthToken_APIKeyPriority(t *testing.T) {
 		t.Errorf("expected APIKey to take priority, got '%s'", token)
 	}
 }
+
+func TestLoad_LegacyFileIsDefaultProfile(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+
+	cfgDir := filepath.Join(tmpDir, ".config", "agenthq")
+	os.MkdirAll(cfgDir, 0700)
+	os.WriteFile(filepath.Join(cfgDir, "config.json"), []byte(`{"hub_url":"https://old.example.com","api_key":"ahq_old"}`), 0600)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Profile != DefaultProfile || cfg.APIKey != "ahq_old" {
+		t.Errorf("expected legacy config as default profile, got %+v", cfg)
+	}
+
+	cfg.OrgID = "org-1"
+	if err := Save(cfg); err != nil {
+		t.Fatalf("unexpected error saving config: %v", err)
+	}
+	f, err := LoadFile()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if p := f.Profiles[DefaultProfile]; p == nil || p.APIKey != "" || p.OrgID != "org-1" {
+		t.Errorf("expected migrated default profile without its API key, got %+v", p)
+	}
+	if f.CredentialStore != StoreEncryptedFile {
+		t.Errorf("expected credentials moved to the encrypted file, got store %q", f.CredentialStore)
+	}
+	cfg, _ = Load()
+	if err := cfg.LoadCredentials(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.APIKey != "ahq_old" || cfg.Sources["api_key"] != "encrypted-file:default" {
+		t.Errorf("expected API key from the encrypted file, got %q from %q", cfg.APIKey, cfg.Sources["api_key"])
+	}
+}
+
+func TestSave_KeepsOtherProfiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+
+	Save(&Config{HubURL: "https://prod.example.com", APIKey: "ahq_prod"})
+
+	Profile = "staging"
+	defer func() { Profile = "" }()
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Profile != "staging" || cfg.APIKey != "" || cfg.HubURL != "http://localhost:3000" {
+		t.Errorf("expected empty staging profile, got %+v", cfg)
+	}
+	cfg.HubURL = "https://staging.example.com"
+	cfg.APIKey = "ahq_staging"
+	if err := Save(cfg); err != nil {
+		t.Fatalf("unexpected error saving config: %v", err)
+	}
+
+	Profile = ""
+	cfg, _ = Load()
+	cfg.LoadCredentials()
+	if cfg.Profile != DefaultProfile || cfg.APIKey != "ahq_prod" {
+		t.Errorf("expected current profile to stay default, got %+v", cfg)
+	}
+
+	f, _ := LoadFile()
+	if got := f.Names(); len(got) != 2 || got[0] != "default" || got[1] != "staging" {
+		t.Errorf("expected [default staging], got %v", got)
+	}
+}
+
+func TestFile_UseRenameDelete(t *testing.T) {
+	f := &File{CurrentProfile: "a", Profiles: map[string]*Config{"a": {}, "b": {}}}
+
+	if err := f.Use("missing"); err == nil {
+		t.Error("expected error using a missing profile")
+	}
+	if err := f.Rename("a", "b"); err == nil {
+		t.Error("expected error renaming onto an existing profile")
+	}
+	if err := f.Rename("a", "c"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if f.CurrentProfile != "c" {
+		t.Errorf("expected current profile to follow rename, got %q", f.CurrentProfile)
+	}
+	if err := f.Delete("c"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if f.CurrentProfile != "" || len(f.Profiles) != 1 {
+		t.Errorf("expected only b left with no current profile, got %+v", f)
+	}
+}
+
+func TestLoad_Overrides(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+	Save(&Config{HubURL: "https://file.example.com", APIKey: "ahq_file", OrgID: "org-file"})
+
+	t.Setenv(EnvHubURL, "https://env.example.com")
+	t.Setenv(EnvAPIKey, "ahq_env")
+	t.Setenv(EnvAgentID, "agent-env")
+	APIKeyOverride = "ahq_flag"
+	defer func() { APIKeyOverride = "" }()
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		key, value, source string
+	}{
+		{"hub_url", "https://env.example.com", "env:HUB_URL"},
+		{"api_key", "ahq_flag", "flag:--api-key"},
+		{"agent_id", "agent-env", "env:AGENTHQ_AGENT_ID"},
+		{"org_id", "org-file", "profile:default"},
+	}
+	for _, tt := range tests {
+		if got := *cfg.fields()[tt.key]; got != tt.value {
+			t.Errorf("%s: expected %q, got %q", tt.key, tt.value, got)
+		}
+		if got := cfg.Sources[tt.key]; got != tt.source {
+			t.Errorf("%s: expected source %q, got %q", tt.key, tt.source, got)
+		}
+	}
+
+	// Saving must not persist overrides, but keeps changed values.
+	cfg.OrgID = "org-new"
+	if err := Save(cfg); err != nil {
+		t.Fatalf("unexpected error saving config: %v", err)
+	}
+	t.Setenv(EnvHubURL, "")
+	t.Setenv(EnvAPIKey, "")
+	t.Setenv(EnvAgentID, "")
+	APIKeyOverride = ""
+	p, err := Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	p.LoadCredentials()
+	if p.HubURL != "https://file.example.com" || p.APIKey != "ahq_file" || p.AgentID != "" {
+		t.Errorf("expected overrides not to be saved, got %+v", p)
+	}
+	if p.OrgID != "org-new" {
+		t.Errorf("expected OrgID='org-new', got '%s'", p.OrgID)
+	}
+}
+
+func TestLoad_DefaultHubURLSource(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Sources["hub_url"] != "default" {
+		t.Errorf("expected hub_url source 'default', got '%s'", cfg.Sources["hub_url"])
+	}
+}
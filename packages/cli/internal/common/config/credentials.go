@@ -0,0 +1,569 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"golang.org/x/crypto/scrypt"
+	"golang.org/x/term"
+)
+
+// Credential store backends, set with the config file's credential_store.
+const (
+	StorePlaintext        = "plaintext"
+	StoreEncryptedFile    = "encrypted-file"
+	StoreCredentialHelper = "credential-helper"
+)
+
+// EnvPassphrase holds the passphrase for an encrypted credentials file
+// protected by a passphrase instead of a key file.
+const EnvPassphrase = "AGENTHQ_PASSPHRASE"
+
+// Credentials are the secrets of one profile, kept out of config.json unless
+// the plaintext store is chosen.
+type Credentials struct {
+	APIKey       string `json:"api_key,omitempty"`
+	JWTToken     string `json:"jwt_token,omitempty"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+}
+
+func (c *Credentials) empty() bool {
+	return c.APIKey == "" && c.JWTToken == "" && c.RefreshToken == ""
+}
+
+// CredentialStore keeps credentials per profile.
+type CredentialStore interface {
+	Get(profile string) (*Credentials, error)
+	Store(profile string, creds *Credentials) error
+	Erase(profile string) error
+}
+
+// fill sets the credentials c is missing from other.
+func (c *Credentials) fill(other *Credentials) {
+	if c.APIKey == "" {
+		c.APIKey = other.APIKey
+	}
+	if c.JWTToken == "" {
+		c.JWTToken = other.JWTToken
+	}
+	if c.RefreshToken == "" {
+		c.RefreshToken = other.RefreshToken
+	}
+}
+
+// StoreName returns the credential store backend in use: the encrypted file
+// unless another was chosen. Plaintext is only used when chosen explicitly.
+func (f *File) StoreName() string {
+	if f.CredentialStore != "" {
+		return f.CredentialStore
+	}
+	return StoreEncryptedFile
+}
+
+// HasPlaintextCredentials reports whether config.json holds credentials
+// although another store is in use. Config files written before credential
+// stores existed do; the next Save or MigrateCredentials moves them.
+func (f *File) HasPlaintextCredentials() bool {
+	if f.StoreName() == StorePlaintext {
+		return false
+	}
+	for _, p := range f.Profiles {
+		if p.APIKey != "" || p.JWTToken != "" || p.RefreshToken != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// takePlaintext removes the credentials HasPlaintextCredentials reports from
+// f and returns them by profile.
+func (f *File) takePlaintext() map[string]*Credentials {
+	all := map[string]*Credentials{}
+	if f.StoreName() == StorePlaintext {
+		return all
+	}
+	for name, p := range f.Profiles {
+		creds := &Credentials{APIKey: p.APIKey, JWTToken: p.JWTToken, RefreshToken: p.RefreshToken}
+		if !creds.empty() {
+			all[name] = creds
+			p.APIKey, p.JWTToken, p.RefreshToken = "", "", ""
+		}
+	}
+	return all
+}
+
+// Store returns the credential store backend in use.
+func (f *File) Store() (CredentialStore, error) {
+	switch name := f.StoreName(); name {
+	case StorePlaintext:
+		return &plaintextStore{file: f}, nil
+	case StoreEncryptedFile:
+		keyFile := f.KeyFile
+		if keyFile == "" {
+			keyFile = filepath.Join(configDir(), "credentials.key")
+		}
+		return &EncryptedFileStore{
+			Path:    filepath.Join(configDir(), "credentials.enc"),
+			KeyFile: keyFile,
+		}, nil
+	case StoreCredentialHelper:
+		if f.CredentialHelper == "" {
+			return nil, errors.New("credential_store is credential-helper but credential_helper is not set")
+		}
+		return &HelperStore{Command: f.CredentialHelper}, nil
+	default:
+		return nil, fmt.Errorf("unknown credential store %q", name)
+	}
+}
+
+// plaintextStore keeps credentials in the profiles of config.json itself.
+type plaintextStore struct {
+	file *File
+}
+
+func (s *plaintextStore) Get(profile string) (*Credentials, error) {
+	p, ok := s.file.Profiles[profile]
+	if !ok {
+		return &Credentials{}, nil
+	}
+	return &Credentials{APIKey: p.APIKey, JWTToken: p.JWTToken, RefreshToken: p.RefreshToken}, nil
+}
+
+func (s *plaintextStore) Store(profile string, creds *Credentials) error {
+	p, ok := s.file.Profiles[profile]
+	if !ok {
+		p = &Config{}
+		s.file.Profiles[profile] = p
+	}
+	p.APIKey, p.JWTToken, p.RefreshToken = creds.APIKey, creds.JWTToken, creds.RefreshToken
+	return nil
+}
+
+func (s *plaintextStore) Erase(profile string) error {
+	if p, ok := s.file.Profiles[profile]; ok {
+		p.APIKey, p.JWTToken, p.RefreshToken = "", "", ""
+	}
+	return nil
+}
+
+// EncryptedFileStore keeps every profile's credentials in one AES-GCM
+// encrypted file. The key is read from KeyFile, which is created on first
+// use, unless the file was written with a passphrase: then the key is
+// derived with scrypt from $AGENTHQ_PASSPHRASE or a terminal prompt.
+type EncryptedFileStore struct {
+	Path    string
+	KeyFile string
+
+	// Passphrase, if set, encrypts a new credentials file with a passphrase
+	// instead of the key file.
+	Passphrase string
+}
+
+// encryptedFile is the on-disk form of an EncryptedFileStore.
+type encryptedFile struct {
+	Version int    `json:"version"`
+	KDF     string `json:"kdf"`
+	Salt    string `json:"salt,omitempty"`
+	Nonce   string `json:"nonce"`
+	Data    string `json:"data"`
+}
+
+const (
+	kdfKeyFile = "key-file"
+	kdfScrypt  = "scrypt"
+)
+
+func (s *EncryptedFileStore) Get(profile string) (*Credentials, error) {
+	all, _, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	if creds, ok := all[profile]; ok {
+		return creds, nil
+	}
+	return &Credentials{}, nil
+}
+
+func (s *EncryptedFileStore) Store(profile string, creds *Credentials) error {
+	all, kdf, err := s.load()
+	if err != nil {
+		return err
+	}
+	if creds.empty() {
+		delete(all, profile)
+	} else {
+		all[profile] = creds
+	}
+	return s.save(all, kdf)
+}
+
+func (s *EncryptedFileStore) Erase(profile string) error {
+	all, kdf, err := s.load()
+	if err != nil {
+		return err
+	}
+	if _, ok := all[profile]; !ok {
+		return nil
+	}
+	delete(all, profile)
+	return s.save(all, kdf)
+}
+
+func (s *EncryptedFileStore) load() (map[string]*Credentials, string, error) {
+	all := map[string]*Credentials{}
+	data, err := os.ReadFile(s.Path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return all, "", nil
+		}
+		return nil, "", err
+	}
+
+	var ef encryptedFile
+	if err := json.Unmarshal(data, &ef); err != nil {
+		return nil, "", fmt.Errorf("reading %s: %w", s.Path, err)
+	}
+	key, err := s.key(ef.KDF, ef.Salt, false)
+	if err != nil {
+		return nil, "", err
+	}
+	nonce, err := base64.StdEncoding.DecodeString(ef.Nonce)
+	if err != nil {
+		return nil, "", fmt.Errorf("reading %s: %w", s.Path, err)
+	}
+	sealed, err := base64.StdEncoding.DecodeString(ef.Data)
+	if err != nil {
+		return nil, "", fmt.Errorf("reading %s: %w", s.Path, err)
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(nonce) != gcm.NonceSize() {
+		return nil, "", fmt.Errorf("reading %s: invalid nonce", s.Path)
+	}
+	plain, err := gcm.Open(nil, nonce, sealed, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("cannot decrypt %s: wrong key or passphrase", s.Path)
+	}
+	if err := json.Unmarshal(plain, &all); err != nil {
+		return nil, "", fmt.Errorf("reading %s: %w", s.Path, err)
+	}
+	return all, ef.KDF, nil
+}
+
+func (s *EncryptedFileStore) save(all map[string]*Credentials, kdf string) error {
+	if kdf == "" {
+		kdf = kdfKeyFile
+		if s.Passphrase != "" || os.Getenv(EnvPassphrase) != "" {
+			kdf = kdfScrypt
+		}
+	}
+
+	ef := encryptedFile{Version: 1, KDF: kdf}
+	if kdf == kdfScrypt {
+		salt := make([]byte, 16)
+		if _, err := rand.Read(salt); err != nil {
+			return err
+		}
+		ef.Salt = base64.StdEncoding.EncodeToString(salt)
+	}
+	key, err := s.key(kdf, ef.Salt, true)
+	if err != nil {
+		return err
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	plain, err := json.Marshal(all)
+	if err != nil {
+		return err
+	}
+	ef.Nonce = base64.StdEncoding.EncodeToString(nonce)
+	ef.Data = base64.StdEncoding.EncodeToString(gcm.Seal(nil, nonce, plain, nil))
+
+	data, err := json.MarshalIndent(ef, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.Path), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(s.Path, data, 0600)
+}
+
+// key returns the AES-256 key for kdf, creating the key file if create is
+// set and it does not exist yet.
+func (s *EncryptedFileStore) key(kdf, salt string, create bool) ([]byte, error) {
+	switch kdf {
+	case kdfKeyFile:
+		return readKeyFile(s.KeyFile, create)
+	case kdfScrypt:
+		passphrase, err := s.passphrase()
+		if err != nil {
+			return nil, err
+		}
+		rawSalt, err := base64.StdEncoding.DecodeString(salt)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", s.Path, err)
+		}
+		return scrypt.Key([]byte(passphrase), rawSalt, 1<<15, 8, 1, 32)
+	default:
+		return nil, fmt.Errorf("reading %s: unknown kdf %q", s.Path, kdf)
+	}
+}
+
+func (s *EncryptedFileStore) passphrase() (string, error) {
+	if s.Passphrase != "" {
+		return s.Passphrase, nil
+	}
+	if p := os.Getenv(EnvPassphrase); p != "" {
+		return p, nil
+	}
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return "", fmt.Errorf("credentials are protected by a passphrase; set %s", EnvPassphrase)
+	}
+	if promptedPassphrase != "" {
+		return promptedPassphrase, nil
+	}
+	p, err := ReadPassphrase("Credential passphrase: ")
+	if err != nil {
+		return "", err
+	}
+	promptedPassphrase = p
+	return p, nil
+}
+
+// promptedPassphrase saves prompting again when one command both loads and
+// saves credentials.
+var promptedPassphrase string
+
+// ReadPassphrase prompts on stderr and reads a line from the terminal
+// without echoing it.
+func ReadPassphrase(prompt string) (string, error) {
+	fmt.Fprint(os.Stderr, prompt)
+	p, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", err
+	}
+	return string(p), nil
+}
+
+func readKeyFile(path string, create bool) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) && create {
+		key := make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return nil, err
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(path, []byte(base64.StdEncoding.EncodeToString(key)+"\n"), 0600); err != nil {
+			return nil, err
+		}
+		return key, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading credential key file: %w", err)
+	}
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil || len(key) != 32 {
+		return nil, fmt.Errorf("credential key file %s must hold 32 base64-encoded bytes", path)
+	}
+	return key, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// HelperStore delegates to an external program, like git's credential
+// helpers. Command is run through the shell with "get", "store" or "erase"
+// appended. It reads key=value lines on stdin (profile, and for store the
+// credentials) and, for get, writes the credentials as key=value lines on
+// stdout, or nothing if it has none. Unknown keys are ignored.
+type HelperStore struct {
+	Command string
+}
+
+func (s *HelperStore) Get(profile string) (*Credentials, error) {
+	out, err := s.run("get", profile, nil)
+	if err != nil {
+		return nil, err
+	}
+	creds := &Credentials{}
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		key, val, ok := strings.Cut(scanner.Text(), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "api_key":
+			creds.APIKey = val
+		case "jwt_token":
+			creds.JWTToken = val
+		case "refresh_token":
+			creds.RefreshToken = val
+		}
+	}
+	return creds, scanner.Err()
+}
+
+func (s *HelperStore) Store(profile string, creds *Credentials) error {
+	if creds.empty() {
+		return s.Erase(profile)
+	}
+	_, err := s.run("store", profile, creds)
+	return err
+}
+
+func (s *HelperStore) Erase(profile string) error {
+	_, err := s.run("erase", profile, nil)
+	return err
+}
+
+func (s *HelperStore) run(op, profile string, creds *Credentials) ([]byte, error) {
+	var in bytes.Buffer
+	fmt.Fprintf(&in, "profile=%s\n", profile)
+	if creds != nil {
+		writeField(&in, "api_key", creds.APIKey)
+		writeField(&in, "jwt_token", creds.JWTToken)
+		writeField(&in, "refresh_token", creds.RefreshToken)
+	}
+
+	command := s.Command + " " + op
+	var c *exec.Cmd
+	if runtime.GOOS == "windows" {
+		c = exec.Command("cmd", "/C", command)
+	} else {
+		c = exec.Command("sh", "-c", command)
+	}
+	c.Stdin = &in
+	c.Stderr = os.Stderr
+	out, err := c.Output()
+	if err != nil {
+		return nil, fmt.Errorf("credential helper %q %s: %w", s.Command, op, err)
+	}
+	return out, nil
+}
+
+func writeField(w io.Writer, key, val string) {
+	if val != "" {
+		fmt.Fprintf(w, "%s=%s\n", key, val)
+	}
+}
+
+// StoreSettings select a credential store backend for MigrateCredentials.
+type StoreSettings struct {
+	Store   string
+	Helper  string
+	KeyFile string
+
+	// Passphrase encrypts the credentials file with a passphrase instead of
+	// the key file.
+	Passphrase string
+}
+
+// MigrateCredentials moves the credentials of every profile from the store
+// in use to the one described by to, then erases them from the old store.
+// Migrating to the encrypted file again re-encrypts it, e.g. to switch
+// between a key file and a passphrase.
+func MigrateCredentials(to StoreSettings) error {
+	f, err := LoadFile()
+	if err != nil {
+		return err
+	}
+	fromName := f.StoreName()
+	if fromName == to.Store && to.Store != StoreEncryptedFile {
+		return fmt.Errorf("credentials are already stored with %s", to.Store)
+	}
+	src, err := f.Store()
+	if err != nil {
+		return err
+	}
+
+	all := f.takePlaintext()
+	for _, name := range f.Names() {
+		creds, err := src.Get(name)
+		if err != nil {
+			return fmt.Errorf("reading credentials of profile %q: %w", name, err)
+		}
+		if plain, ok := all[name]; ok {
+			creds.fill(plain)
+		}
+		if !creds.empty() {
+			all[name] = creds
+		}
+	}
+
+	// Plaintext credentials live in f itself, so drop them before any
+	// plaintext destination writes them back.
+	if fromName == StorePlaintext {
+		for _, name := range f.Names() {
+			src.Erase(name)
+		}
+	}
+
+	f.CredentialStore, f.CredentialHelper, f.KeyFile = to.Store, to.Helper, to.KeyFile
+	dst, err := f.Store()
+	if err != nil {
+		return err
+	}
+	if enc, ok := dst.(*EncryptedFileStore); ok {
+		enc.Passphrase = to.Passphrase
+		if err := enc.save(all, ""); err != nil {
+			return fmt.Errorf("saving credentials: %w", err)
+		}
+	} else {
+		for name, creds := range all {
+			if err := dst.Store(name, creds); err != nil {
+				return fmt.Errorf("saving credentials of profile %q: %w", name, err)
+			}
+		}
+	}
+	if err := SaveFile(f); err != nil {
+		return err
+	}
+
+	switch old := src.(type) {
+	case *EncryptedFileStore:
+		if fromName == to.Store {
+			return nil
+		}
+		if err := os.Remove(old.Path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("credentials migrated, but removing %s failed: %w", old.Path, err)
+		}
+	case *HelperStore:
+		for name := range all {
+			if err := old.Erase(name); err != nil {
+				return fmt.Errorf("credentials migrated, but erasing them from the old helper failed: %w", err)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,225 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestEncryptedFileStore_KeyFile(t *testing.T) {
+	dir := t.TempDir()
+	s := &EncryptedFileStore{Path: filepath.Join(dir, "credentials.enc"), KeyFile: filepath.Join(dir, "credentials.key")}
+
+	if err := s.Store("default", &Credentials{APIKey: "ahq_secret"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, _ := os.ReadFile(s.Path)
+	if strings.Contains(string(data), "ahq_secret") {
+		t.Errorf("expected credentials to be encrypted, got %s", data)
+	}
+
+	creds, err := s.Get("default")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if creds.APIKey != "ahq_secret" {
+		t.Errorf("expected APIKey='ahq_secret', got '%s'", creds.APIKey)
+	}
+
+	other := &EncryptedFileStore{Path: s.Path, KeyFile: filepath.Join(dir, "other.key")}
+	readKeyFile(other.KeyFile, true)
+	if _, err := other.Get("default"); err == nil {
+		t.Error("expected error decrypting with the wrong key file")
+	}
+}
+
+func TestEncryptedFileStore_Passphrase(t *testing.T) {
+	dir := t.TempDir()
+	s := &EncryptedFileStore{Path: filepath.Join(dir, "credentials.enc"), KeyFile: filepath.Join(dir, "credentials.key"), Passphrase: "correct horse"}
+
+	if err := s.Store("default", &Credentials{JWTToken: "jwt"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := os.Stat(s.KeyFile); !os.IsNotExist(err) {
+		t.Error("expected no key file when using a passphrase")
+	}
+
+	t.Setenv(EnvPassphrase, "correct horse")
+	creds, err := (&EncryptedFileStore{Path: s.Path}).Get("default")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if creds.JWTToken != "jwt" {
+		t.Errorf("expected JWTToken='jwt', got '%s'", creds.JWTToken)
+	}
+
+	t.Setenv(EnvPassphrase, "wrong")
+	if _, err := (&EncryptedFileStore{Path: s.Path}).Get("default"); err == nil {
+		t.Error("expected error decrypting with the wrong passphrase")
+	}
+}
+
+func TestHelperStore(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("helper script needs sh")
+	}
+	dir := t.TempDir()
+	script := filepath.Join(dir, "helper")
+	os.WriteFile(script, []byte(`#!/bin/sh
+read line
+f="`+dir+`/${line#profile=}"
+case $1 in
+get) cat "$f" 2>/dev/null || true ;;
+store) cat > "$f" ;;
+erase) rm -f "$f" ;;
+esac
+`), 0700)
+
+	s := &HelperStore{Command: script}
+	if err := s.Store("work", &Credentials{APIKey: "ahq_helper", RefreshToken: "rt"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	creds, err := s.Get("work")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if creds.APIKey != "ahq_helper" || creds.RefreshToken != "rt" {
+		t.Errorf("expected stored credentials, got %+v", creds)
+	}
+
+	if err := s.Erase("work"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	creds, err = s.Get("work")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if creds.APIKey != "" {
+		t.Errorf("expected erased credentials, got %+v", creds)
+	}
+
+	if _, err := (&HelperStore{Command: "exit 1;"}).Get("work"); err == nil {
+		t.Error("expected error from a failing helper")
+	}
+}
+
+func TestMigrateCredentials_FromPlaintext(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+
+	cfgDir := filepath.Join(tmpDir, ".config", "agenthq")
+	os.MkdirAll(cfgDir, 0700)
+	os.WriteFile(filepath.Join(cfgDir, "config.json"), []byte(`{"hub_url":"https://hub.example.com","api_key":"ahq_plain"}`), 0600)
+
+	if err := MigrateCredentials(StoreSettings{Store: StoreEncryptedFile}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, _ := os.ReadFile(filepath.Join(cfgDir, "config.json"))
+	if strings.Contains(string(data), "ahq_plain") {
+		t.Errorf("expected API key removed from config.json, got %s", data)
+	}
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := cfg.LoadCredentials(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.APIKey != "ahq_plain" || cfg.HubURL != "https://hub.example.com" {
+		t.Errorf("expected migrated credentials, got %+v", cfg)
+	}
+	if cfg.Sources["api_key"] != "encrypted-file:default" {
+		t.Errorf("expected api_key source 'encrypted-file:default', got '%s'", cfg.Sources["api_key"])
+	}
+
+	if err := MigrateCredentials(StoreSettings{Store: StorePlaintext}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(cfgDir, "credentials.enc")); !os.IsNotExist(err) {
+		t.Error("expected credentials.enc removed after migrating away")
+	}
+	cfg, _ = Load()
+	if cfg.APIKey != "ahq_plain" {
+		t.Errorf("expected APIKey back in plaintext, got '%s'", cfg.APIKey)
+	}
+}
+
+func TestSave_MovesPlaintextCredentials(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+
+	SaveFile(&File{CurrentProfile: "work", Profiles: map[string]*Config{
+		"work":     {HubURL: "https://work.example.com", APIKey: "ahq_work"},
+		"personal": {HubURL: "https://home.example.com", JWTToken: "jwt_home"},
+	}})
+	f, _ := LoadFile()
+	if !f.HasPlaintextCredentials() {
+		t.Fatal("expected plaintext credentials to be reported")
+	}
+
+	if err := Save(&Config{HubURL: "https://work.example.com", APIKey: "ahq_new"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, _ := os.ReadFile(filepath.Join(tmpDir, ".config", "agenthq", "config.json"))
+	if strings.Contains(string(data), "ahq_") || strings.Contains(string(data), "jwt_home") {
+		t.Errorf("expected credentials removed from config.json, got %s", data)
+	}
+
+	Profile = "personal"
+	defer func() { Profile = "" }()
+	cfg, _ := Load()
+	if err := cfg.LoadCredentials(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.JWTToken != "jwt_home" {
+		t.Errorf("expected the other profile's token in the store, got %+v", cfg)
+	}
+}
+
+func TestLoad_CredentialsReadLazily(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+	t.Setenv(EnvPassphrase, "")
+
+	cfgDir := filepath.Join(tmpDir, ".config", "agenthq")
+	os.MkdirAll(cfgDir, 0700)
+	os.WriteFile(filepath.Join(cfgDir, "config.json"), []byte(`{"version":1,"credential_store":"encrypted-file","profiles":{"default":{"hub_url":"https://hub.example.com"}}}`), 0600)
+	s := &EncryptedFileStore{Path: filepath.Join(cfgDir, "credentials.enc"), Passphrase: "secret"}
+	if err := s.Store("default", &Credentials{APIKey: "ahq_locked", RefreshToken: "rt"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Without the passphrase, settings still load and save.
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.HubURL != "https://hub.example.com" || cfg.APIKey != "" {
+		t.Errorf("expected settings without credentials, got %+v", cfg)
+	}
+	cfg.OrgID = "org-new"
+	if err := Save(cfg); err != nil {
+		t.Fatalf("unexpected error saving config: %v", err)
+	}
+
+	t.Setenv(EnvPassphrase, "secret")
+	cfg, _ = Load()
+	cfg.JWTToken = "jwt-new"
+	if err := Save(cfg); err != nil {
+		t.Fatalf("unexpected error saving config: %v", err)
+	}
+
+	cfg, _ = Load()
+	if err := cfg.LoadCredentials(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.OrgID != "org-new" || cfg.APIKey != "ahq_locked" || cfg.JWTToken != "jwt-new" || cfg.RefreshToken != "rt" {
+		t.Errorf("expected saved settings and merged credentials, got %+v", cfg)
+	}
+	if cfg.Sources["api_key"] != "encrypted-file:default" {
+		t.Errorf("expected api_key source 'encrypted-file:default', got '%s'", cfg.Sources["api_key"])
+	}
+}
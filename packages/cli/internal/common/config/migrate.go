@@ -0,0 +1,70 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// CurrentVersion is the config file format written by this version.
+//
+//	0: a single flat set of settings, without a version field
+//	1: named profiles and preferences
+const CurrentVersion = 1
+
+// migrations[i] upgrades a raw config file from version i to i+1.
+var migrations = []func(raw map[string]json.RawMessage) error{
+	migrateToProfiles,
+}
+
+// migrate upgrades a config file to CurrentVersion, returning the upgraded
+// JSON and the version it was read as.
+func migrate(data []byte) ([]byte, int, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, 0, err
+	}
+	version := fileVersion(raw)
+	if version > CurrentVersion {
+		return nil, version, fmt.Errorf("config file version %d is newer than this CLI supports (%d); upgrade agenthq", version, CurrentVersion)
+	}
+	if version == CurrentVersion {
+		return data, version, nil
+	}
+
+	for v := version; v < CurrentVersion; v++ {
+		if err := migrations[v](raw); err != nil {
+			return nil, version, fmt.Errorf("migrating config from version %d: %w", v, err)
+		}
+	}
+	raw["version"] = json.RawMessage(fmt.Sprint(CurrentVersion))
+	out, err := json.Marshal(raw)
+	return out, version, err
+}
+
+func fileVersion(raw map[string]json.RawMessage) int {
+	if v, ok := raw["version"]; ok {
+		var version int
+		if json.Unmarshal(v, &version) == nil {
+			return version
+		}
+	}
+	return 0
+}
+
+// migrateToProfiles moves flat settings into a profile named "default".
+func migrateToProfiles(raw map[string]json.RawMessage) error {
+	profile, err := json.Marshal(raw)
+	if err != nil {
+		return err
+	}
+	for key := range raw {
+		delete(raw, key)
+	}
+	profiles, err := json.Marshal(map[string]json.RawMessage{DefaultProfile: profile})
+	if err != nil {
+		return err
+	}
+	raw["current_profile"] = json.RawMessage(fmt.Sprintf("%q", DefaultProfile))
+	raw["profiles"] = profiles
+	return nil
+}
@@ -0,0 +1,75 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Project file names, looked for in the working directory and its parents.
+var projectFiles = []string{".agenthq.json", filepath.Join(".agenthq", "config.json")}
+
+// Project is a project-local config. Its org and agent are merged over the
+// active profile. It cannot set the hub URL: the profile's credentials would
+// be sent to whatever hub a checked-out repository names.
+type Project struct {
+	Profile string `json:"profile,omitempty"`
+	OrgID   string `json:"org_id,omitempty"`
+	AgentID string `json:"agent_id,omitempty"`
+
+	DefaultChannel      string `json:"default_channel,omitempty"`
+	DefaultPostType     string `json:"default_post_type,omitempty"`
+	DefaultTaskAssignee string `json:"default_task_assignee,omitempty"`
+
+	// Path is the file the project config was read from.
+	Path string `json:"-"`
+}
+
+// FindProject returns the first project file found in dir or its parents,
+// or nil if there is none.
+func FindProject(dir string) (*Project, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		for _, name := range projectFiles {
+			path := filepath.Join(dir, name)
+			data, err := os.ReadFile(path)
+			if os.IsNotExist(err) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			p := &Project{Path: path}
+			if err := json.Unmarshal(data, p); err != nil {
+				return nil, fmt.Errorf("reading %s: %w", path, err)
+			}
+			return p, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, nil
+		}
+		dir = parent
+	}
+}
+
+// LoadProject returns the project config for the working directory. With no
+// project file it returns an empty Project.
+func LoadProject() (*Project, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return &Project{}, nil
+	}
+	p, err := FindProject(wd)
+	if err != nil {
+		return nil, err
+	}
+	if p == nil {
+		return &Project{}, nil
+	}
+	return p, nil
+}
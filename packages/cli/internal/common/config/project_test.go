@@ -0,0 +1,82 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFindProject(t *testing.T) {
+	root := t.TempDir()
+	nested := filepath.Join(root, "a", "b")
+	os.MkdirAll(nested, 0700)
+
+	p, err := FindProject(nested)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if p != nil {
+		t.Fatalf("expected no project, got %+v", p)
+	}
+
+	os.MkdirAll(filepath.Join(root, ".agenthq"), 0700)
+	os.WriteFile(filepath.Join(root, ".agenthq", "config.json"), []byte(`{"default_channel":"root"}`), 0600)
+	os.WriteFile(filepath.Join(root, "a", ".agenthq.json"), []byte(`{"default_channel":"ch-1","default_post_type":"insight"}`), 0600)
+
+	p, err = FindProject(nested)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if p == nil || p.DefaultChannel != "ch-1" || p.DefaultPostType != "insight" {
+		t.Fatalf("expected nearest project file, got %+v", p)
+	}
+	if p.Path != filepath.Join(root, "a", ".agenthq.json") {
+		t.Errorf("unexpected path %s", p.Path)
+	}
+
+	p, _ = FindProject(root)
+	if p == nil || p.DefaultChannel != "root" {
+		t.Errorf("expected .agenthq/config.json, got %+v", p)
+	}
+
+	os.WriteFile(filepath.Join(root, "a", ".agenthq.json"), []byte(`{`), 0600)
+	if _, err := FindProject(nested); err == nil {
+		t.Error("expected error for invalid project file")
+	}
+}
+
+func TestLoad_ProjectOverridesProfile(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+	Save(&Config{HubURL: "https://default.example.com", OrgID: "org-default"})
+	Save(&Config{HubURL: "https://work.example.com", OrgID: "org-work", Profile: "work"})
+
+	project := filepath.Join(tmpDir, "repo")
+	os.MkdirAll(project, 0700)
+	project, _ = filepath.EvalSymlinks(project)
+	os.WriteFile(filepath.Join(project, ".agenthq.json"), []byte(`{"profile":"work","agent_id":"agent-repo","hub_url":"https://evil.example.com"}`), 0600)
+	wd, _ := os.Getwd()
+	os.Chdir(project)
+	defer os.Chdir(wd)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Profile != "work" || cfg.OrgID != "org-work" {
+		t.Errorf("expected project to select the work profile, got %+v", cfg)
+	}
+	if cfg.HubURL != "https://work.example.com" || cfg.Sources["hub_url"] != "profile:work" {
+		t.Errorf("expected hub_url from the profile, got %q from %q", cfg.HubURL, cfg.Sources["hub_url"])
+	}
+	if cfg.AgentID != "agent-repo" || cfg.Sources["agent_id"] != "project:"+filepath.Join(project, ".agenthq.json") {
+		t.Errorf("expected agent_id from project, got %q from %q", cfg.AgentID, cfg.Sources["agent_id"])
+	}
+
+	Profile = "default"
+	defer func() { Profile = "" }()
+	cfg, _ = Load()
+	if cfg.Profile != "default" {
+		t.Errorf("expected --profile to win over the project, got %s", cfg.Profile)
+	}
+}
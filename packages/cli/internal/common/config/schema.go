@@ -0,0 +1,316 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Gahroot/agentHQ-cli/pkg/output"
+)
+
+// Key types.
+const (
+	TypeString   = "string"
+	TypeURL      = "url"
+	TypeBool     = "bool"
+	TypeInt      = "int"
+	TypeDuration = "duration"
+	TypeEnum     = "enum"
+)
+
+// Key scopes: profile keys belong to the active profile, global keys to the
+// whole config file.
+const (
+	ScopeProfile = "profile"
+	ScopeGlobal  = "global"
+)
+
+// Key describes a config key.
+type Key struct {
+	Name        string
+	Type        string
+	Scope       string
+	Description string
+	Default     string
+	// Values lists the allowed values of an enum key.
+	Values []string
+	// Secret values are masked when shown and kept in the credential store.
+	Secret bool
+	// ManagedBy names the command that changes a key config set cannot.
+	ManagedBy string
+
+	validate func(string) error
+}
+
+// Keys is the registry of config keys.
+var Keys = []*Key{
+	{Name: "hub_url", Type: TypeURL, Scope: ScopeProfile, Default: defaultHubURL, Description: "Hub URL"},
+	{Name: "api_key", Type: TypeString, Scope: ScopeProfile, Secret: true, Description: "Agent API key", validate: validateAPIKey},
+	{Name: "jwt_token", Type: TypeString, Scope: ScopeProfile, Secret: true, Description: "User access token"},
+	{Name: "refresh_token", Type: TypeString, Scope: ScopeProfile, Secret: true, Description: "User refresh token"},
+	{Name: "org_id", Type: TypeString, Scope: ScopeProfile, Description: "Organization ID"},
+	{Name: "agent_id", Type: TypeString, Scope: ScopeProfile, Description: "Agent ID"},
+
+	{Name: "output", Type: TypeEnum, Scope: ScopeGlobal, Default: output.FormatTable, Values: output.Formats, Description: "Default output format"},
+	{Name: "timeout", Type: TypeDuration, Scope: ScopeGlobal, Default: "30s", Description: "Default timeout for each request to the hub", validate: validateNonNegativeDuration},
+	{Name: "max_attempts", Type: TypeInt, Scope: ScopeGlobal, Default: "3", Description: "Default maximum attempts per request", validate: validatePositiveInt},
+	{Name: "debug", Type: TypeBool, Scope: ScopeGlobal, Default: "false", Description: "Trace hub requests and responses to stderr"},
+	{Name: "editor", Type: TypeString, Scope: ScopeGlobal, Description: "Editor for config edit and composing content (default: $VISUAL, $EDITOR, then vi)"},
+
+	{Name: "credential_store", Type: TypeEnum, Scope: ScopeGlobal, Values: []string{StoreEncryptedFile, StoreCredentialHelper, StorePlaintext}, Description: "Where API keys and tokens are kept", ManagedBy: "agenthq config migrate-credentials --to"},
+	{Name: "credential_helper", Type: TypeString, Scope: ScopeGlobal, Description: "Credential helper command", ManagedBy: "agenthq config migrate-credentials --helper"},
+	{Name: "credential_key_file", Type: TypeString, Scope: ScopeGlobal, Description: "Key file of the encrypted credential store (default credentials.key beside credentials.enc, so copying the config directory copies both)", ManagedBy: "agenthq config migrate-credentials --key-file"},
+}
+
+// LookupKey returns the registered key called name.
+func LookupKey(name string) (*Key, bool) {
+	for _, k := range Keys {
+		if k.Name == name {
+			return k, true
+		}
+	}
+	return nil, false
+}
+
+// Validate checks val against the key's type.
+func (k *Key) Validate(val string) error {
+	var err error
+	switch k.Type {
+	case TypeURL:
+		u, perr := url.Parse(val)
+		if perr != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			err = fmt.Errorf("must be an http or https URL")
+		}
+	case TypeBool:
+		if _, perr := strconv.ParseBool(val); perr != nil {
+			err = fmt.Errorf("must be true or false")
+		}
+	case TypeInt:
+		if _, perr := strconv.Atoi(val); perr != nil {
+			err = fmt.Errorf("must be a whole number")
+		}
+	case TypeDuration:
+		if _, perr := time.ParseDuration(val); perr != nil {
+			err = fmt.Errorf("must be a duration such as 30s or 2m")
+		}
+	case TypeEnum:
+		err = fmt.Errorf("must be one of %s", strings.Join(k.Values, ", "))
+		for _, v := range k.Values {
+			if v == val {
+				err = nil
+			}
+		}
+	}
+	if err == nil && k.validate != nil {
+		err = k.validate(val)
+	}
+	if err != nil {
+		return fmt.Errorf("invalid %s %q: %w", k.Name, val, err)
+	}
+	return nil
+}
+
+// Mask hides secret values. API keys keep their prefix so keys can be told
+// apart; other secrets are hidden entirely.
+func (k *Key) Mask(val string) string {
+	if !k.Secret || val == "" {
+		return val
+	}
+	if strings.HasPrefix(val, "ahq_") && len(val) > 12 {
+		return val[:12] + "..."
+	}
+	return "***set***"
+}
+
+func validateAPIKey(val string) error {
+	if !strings.HasPrefix(val, "ahq_") {
+		return fmt.Errorf("agent API keys start with ahq_")
+	}
+	return nil
+}
+
+func validateNonNegativeDuration(val string) error {
+	if d, _ := time.ParseDuration(val); d < 0 {
+		return fmt.Errorf("must not be negative")
+	}
+	return nil
+}
+
+func validatePositiveInt(val string) error {
+	if n, _ := strconv.Atoi(val); n < 1 {
+		return fmt.Errorf("must be at least 1")
+	}
+	return nil
+}
+
+// Value returns the value of a profile key.
+func (c *Config) Value(key string) string {
+	if v, ok := c.fields()[key]; ok {
+		return *v
+	}
+	return ""
+}
+
+// SetValue sets a profile key. Empty unsets it.
+func (c *Config) SetValue(key, val string) error {
+	v, ok := c.fields()[key]
+	if !ok {
+		return fmt.Errorf("%s is not a profile key", key)
+	}
+	*v = val
+	return nil
+}
+
+// Value returns the value of a global key, and whether it is set.
+func (f *File) Value(key string) (string, bool) {
+	var val string
+	switch key {
+	case "credential_store":
+		val = f.StoreName()
+		return val, f.CredentialStore != ""
+	case "credential_helper":
+		val = f.CredentialHelper
+	case "credential_key_file":
+		val = f.KeyFile
+	default:
+		val = f.Preferences[key]
+	}
+	return val, val != ""
+}
+
+// SetPreference sets a global preference. Empty unsets it.
+func (f *File) SetPreference(key, val string) {
+	if val == "" {
+		delete(f.Preferences, key)
+		return
+	}
+	if f.Preferences == nil {
+		f.Preferences = map[string]string{}
+	}
+	f.Preferences[key] = val
+}
+
+// Preference returns a global preference, or its default if unset.
+func Preference(key string) string {
+	k, ok := LookupKey(key)
+	if !ok {
+		return ""
+	}
+	f, err := LoadFile()
+	if err != nil {
+		return k.Default
+	}
+	if val, ok := f.Value(key); ok {
+		return val
+	}
+	return k.Default
+}
+
+// Problem is a config value that fails validation.
+type Problem struct {
+	// Where is "profile <name>", "preferences" or the top-level field.
+	Where string `json:"where"`
+	Key   string `json:"key"`
+	Error string `json:"error"`
+}
+
+// Validate checks every stored value against the key registry.
+func (f *File) Validate() []Problem {
+	var problems []Problem
+	check := func(where, key, val string, scope string) {
+		k, ok := LookupKey(key)
+		switch {
+		case !ok || k.Scope != scope:
+			problems = append(problems, Problem{Where: where, Key: key, Error: "unknown key"})
+		case where == "preferences" && k.ManagedBy != "":
+			problems = append(problems, Problem{Where: where, Key: key, Error: "not a preference; set with " + k.ManagedBy})
+		case val != "":
+			if err := k.Validate(val); err != nil {
+				problems = append(problems, Problem{Where: where, Key: key, Error: err.Error()})
+			}
+		}
+	}
+
+	if f.CurrentProfile != "" {
+		if _, ok := f.Profiles[f.CurrentProfile]; !ok {
+			problems = append(problems, Problem{Where: "current_profile", Key: "current_profile", Error: fmt.Sprintf("profile %q does not exist", f.CurrentProfile)})
+		}
+	}
+	for _, name := range f.Names() {
+		p := f.Profiles[name]
+		if p == nil {
+			// LoadFile treats a null profile as an empty one.
+			continue
+		}
+		for key, val := range p.fields() {
+			check("profile "+name, key, *val, ScopeProfile)
+		}
+	}
+	for key, val := range f.Preferences {
+		check("preferences", key, val, ScopeGlobal)
+	}
+	if f.CredentialStore != "" {
+		check("credential_store", "credential_store", f.CredentialStore, ScopeGlobal)
+	}
+	if f.CredentialStore == StoreCredentialHelper && f.CredentialHelper == "" {
+		problems = append(problems, Problem{Where: "credential_helper", Key: "credential_helper", Error: "required with credential_store credential-helper"})
+	}
+	sortProblems(problems)
+	return problems
+}
+
+// ValidateData checks a config file's contents: that it parses, and that
+// every key is known and valid.
+func ValidateData(data []byte) ([]Problem, error) {
+	migrated, _, err := migrate(data)
+	if err != nil {
+		return nil, err
+	}
+	f := &File{}
+	if err := json.Unmarshal(migrated, f); err != nil {
+		return nil, err
+	}
+	problems := f.Validate()
+
+	var raw struct {
+		Profiles map[string]map[string]json.RawMessage `json:"profiles"`
+	}
+	if err := json.Unmarshal(migrated, &raw); err != nil {
+		return nil, err
+	}
+	for name, p := range raw.Profiles {
+		for key := range p {
+			if k, ok := LookupKey(key); !ok || k.Scope != ScopeProfile {
+				problems = append(problems, Problem{Where: "profile " + name, Key: key, Error: "unknown key"})
+			}
+		}
+	}
+	sortProblems(problems)
+	return problems, nil
+}
+
+// ValidateFile checks the config file. A missing file is valid.
+func ValidateFile() ([]Problem, error) {
+	data, err := os.ReadFile(ConfigPath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return ValidateData(data)
+}
+
+func sortProblems(problems []Problem) {
+	sort.Slice(problems, func(i, j int) bool {
+		if problems[i].Where != problems[j].Where {
+			return problems[i].Where < problems[j].Where
+		}
+		return problems[i].Key < problems[j].Key
+	})
+}
@@ -0,0 +1,131 @@
+package config
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestKeyValidate(t *testing.T) {
+	tests := []struct {
+		key, value string
+		ok         bool
+	}{
+		{"hub_url", "https://hub.example.com", true},
+		{"hub_url", "hub.example.com", false},
+		{"api_key", "ahq_abc", true},
+		{"api_key", "sk_abc", false},
+		{"output", "json", true},
+		{"output", "wide", true},
+		{"output", "xml", false},
+		{"timeout", "10s", true},
+		{"timeout", "-1s", false},
+		{"timeout", "ten", false},
+		{"max_attempts", "5", true},
+		{"max_attempts", "0", false},
+		{"debug", "true", true},
+		{"debug", "maybe", false},
+	}
+	for _, tt := range tests {
+		k, ok := LookupKey(tt.key)
+		if !ok {
+			t.Fatalf("key %s not registered", tt.key)
+		}
+		if err := k.Validate(tt.value); (err == nil) != tt.ok {
+			t.Errorf("Validate(%s=%q): expected ok=%v, got %v", tt.key, tt.value, tt.ok, err)
+		}
+	}
+}
+
+func TestKeyMask(t *testing.T) {
+	apiKey, _ := LookupKey("api_key")
+	hubURL, _ := LookupKey("hub_url")
+
+	tests := []struct {
+		key   *Key
+		value string
+		want  string
+	}{
+		{apiKey, "ahq_0123456789abcdef", "ahq_01234567..."},
+		{apiKey, "ahq_short", "***set***"},
+		{apiKey, "", ""},
+		{hubURL, "https://hub.example.com", "https://hub.example.com"},
+	}
+	for _, tt := range tests {
+		if got := tt.key.Mask(tt.value); got != tt.want {
+			t.Errorf("Mask(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestValidateData(t *testing.T) {
+	data := []byte(`{
+		"version": 1,
+		"current_profile": "missing",
+		"preferences": {"output": "xml", "colour": "on", "credential_store": "plaintext"},
+		"profiles": {"default": {"hub_url": "ftp://x", "api_kee": "ahq_x"}}
+	}`)
+	problems, err := ValidateData(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var got []string
+	for _, p := range problems {
+		got = append(got, p.Where+"/"+p.Key)
+	}
+	want := []string{
+		"current_profile/current_profile",
+		"preferences/colour",
+		"preferences/credential_store",
+		"preferences/output",
+		"profile default/api_kee",
+		"profile default/hub_url",
+	}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("expected problems %v, got %v", want, got)
+	}
+
+	problems, err = ValidateData([]byte(`{"version":1,"current_profile":"x","profiles":{"x":null}}`))
+	if err != nil || len(problems) != 0 {
+		t.Errorf("expected a null profile to be valid, got %v, %v", problems, err)
+	}
+
+	if _, err := ValidateData([]byte(`{`)); err == nil {
+		t.Error("expected error for invalid JSON")
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		version int
+	}{
+		{"flat", `{"hub_url":"https://a","api_key":"ahq_x"}`, 0},
+		{"current", `{"version":1,"current_profile":"default","profiles":{"default":{"hub_url":"https://a","api_key":"ahq_x"}}}`, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, from, err := migrate([]byte(tt.data))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if from != tt.version {
+				t.Errorf("expected version %d, got %d", tt.version, from)
+			}
+			var f File
+			if err := json.Unmarshal(out, &f); err != nil {
+				t.Fatalf("invalid migrated JSON: %v", err)
+			}
+			p := f.Profiles[DefaultProfile]
+			if f.Version != CurrentVersion || f.CurrentProfile != DefaultProfile || p == nil || p.APIKey != "ahq_x" {
+				t.Errorf("unexpected migrated config: %s", out)
+			}
+		})
+	}
+
+	if _, _, err := migrate([]byte(`{"version":99}`)); err == nil {
+		t.Error("expected error for a newer config version")
+	}
+}
@@ -1,15 +1,100 @@
 package main
 
 import (
-	"fmt"
+	"context"
+	"errors"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/Gahroot/agentHQ-cli/internal/cli"
+	"github.com/Gahroot/agentHQ-cli/internal/common/client"
+	"github.com/Gahroot/agentHQ-cli/pkg/output"
+)
+
+// Exit codes. Scripts may rely on these; do not renumber them.
+const (
+	exitOK       = 0   // success
+	exitError    = 1   // any failure not covered below
+	exitUsage    = 2   // invalid flags, arguments or input, locally or per the hub (HTTP 400/422)
+	exitAuth     = 3   // missing, invalid or expired credentials, or access denied (HTTP 401/403)
+	exitNotFound = 4   // the resource does not exist (HTTP 404)
+	exitAPI      = 5   // any other error response from the hub, e.g. 409, 429 or 5xx
+	exitNetwork  = 6   // the hub could not be reached or timed out
+	exitCanceled = 130 // interrupted by Ctrl-C or SIGTERM
 )
 
 func main() {
-	if err := cli.NewRootCmd().Execute(); err != nil {
-		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+	// Cancel in-flight requests on Ctrl-C or SIGTERM instead of waiting for
+	// the hub to answer.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := cli.NewRootCmd().ExecuteContext(ctx); err != nil {
+		stop()
+		output.PrintErrorInfo(errorInfo(err))
+		os.Exit(exitCode(err))
+	}
+}
+
+// exitCode maps err to one of the documented exit codes.
+func exitCode(err error) int {
+	var apiErr *client.APIError
+	var validationErr *client.ValidationError
+	var authErr *client.AuthError
+	var networkErr *client.NetworkError
+	var columnErr *output.ColumnError
+
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.Is(err, context.Canceled):
+		return exitCanceled
+	case errors.As(err, &validationErr), errors.As(err, &columnErr):
+		return exitUsage
+	case errors.As(err, &authErr):
+		return exitAuth
+	case errors.As(err, &apiErr):
+		switch {
+		case apiErr.IsAuth():
+			return exitAuth
+		case apiErr.StatusCode == 404:
+			return exitNotFound
+		case apiErr.StatusCode == 400 || apiErr.StatusCode == 422:
+			return exitUsage
+		}
+		return exitAPI
+	case errors.As(err, &networkErr), errors.Is(err, context.DeadlineExceeded):
+		return exitNetwork
+	}
+	return exitError
+}
+
+// errorInfo describes err for output, keeping the hub's error code.
+func errorInfo(err error) output.ErrorInfo {
+	info := output.ErrorInfo{Message: err.Error()}
+
+	var apiErr *client.APIError
+	var validationErr *client.ValidationError
+	var authErr *client.AuthError
+	var networkErr *client.NetworkError
+	var columnErr *output.ColumnError
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		info.Code = "CANCELED"
+	case errors.As(err, &validationErr), errors.As(err, &columnErr):
+		info.Code = "VALIDATION_ERROR"
+	case errors.As(err, &authErr):
+		info.Code = "UNAUTHORIZED"
+	case errors.As(err, &apiErr):
+		info.Code = apiErr.Code
+		info.HTTPStatus = apiErr.StatusCode
+		if len(apiErr.Details) > 0 {
+			info.Details = apiErr.Details
+		}
+	case errors.As(err, &networkErr), errors.Is(err, context.DeadlineExceeded):
+		info.Code = "NETWORK_ERROR"
 	}
+	return info
 }
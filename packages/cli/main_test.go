@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/Gahroot/agentHQ-cli/internal/common/client"
+	"github.com/Gahroot/agentHQ-cli/pkg/output"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"nil", nil, exitOK},
+		{"plain", errors.New("boom"), exitError},
+		{"validation", &client.ValidationError{Message: "--title is required"}, exitUsage},
+		{"unknown column", &output.ColumnError{Name: "owner"}, exitUsage},
+		{"hub validation", &client.APIError{Code: "VALIDATION_ERROR", StatusCode: 400}, exitUsage},
+		{"local auth", &client.AuthError{Message: "Not logged in"}, exitAuth},
+		{"unauthorized", &client.APIError{Code: "UNAUTHORIZED", StatusCode: 401}, exitAuth},
+		{"forbidden", &client.APIError{Code: "FORBIDDEN", StatusCode: 403}, exitAuth},
+		{"not found wrapped", fmt.Errorf("Failed to get task: %w", &client.APIError{Code: "NOT_FOUND", StatusCode: 404}), exitNotFound},
+		{"rate limited", &client.APIError{Code: "RATE_LIMITED", StatusCode: 429}, exitAPI},
+		{"server error", &client.APIError{StatusCode: 500}, exitAPI},
+		{"network", &client.NetworkError{Err: errors.New("connection refused")}, exitNetwork},
+		{"timeout", &client.NetworkError{Err: context.DeadlineExceeded}, exitNetwork},
+		{"canceled", &client.NetworkError{Err: context.Canceled}, exitCanceled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := exitCode(tt.err); got != tt.want {
+				t.Errorf("exitCode() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestErrorInfo_PreservesHubCode(t *testing.T) {
+	err := fmt.Errorf("Failed to get task: %w", &client.APIError{Code: "NOT_FOUND", Message: "Task not found", StatusCode: 404})
+	info := errorInfo(err)
+	if info.Code != "NOT_FOUND" || info.HTTPStatus != 404 {
+		t.Errorf("expected NOT_FOUND/404, got %+v", info)
+	}
+	if info.Message != "Failed to get task: NOT_FOUND: Task not found" {
+		t.Errorf("unexpected message %q", info.Message)
+	}
+}
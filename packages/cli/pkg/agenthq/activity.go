@@ -0,0 +1,94 @@
+package agenthq
+
+import (
+	"context"
+)
+
+// ActivityService talks to /api/v1/activity.
+type ActivityService struct{ c *Client }
+
+// ActivityLogParams is the body for logging an activity entry.
+type ActivityLogParams struct {
+	Action       string                 `json:"action"`
+	ResourceType string                 `json:"resource_type,omitempty"`
+	ResourceID   string                 `json:"resource_id,omitempty"`
+	Details      map[string]interface{} `json:"details,omitempty"`
+}
+
+// ActivityListParams filters Activity.List. From and To are ISO 8601 times.
+type ActivityListParams struct {
+	ListOptions
+	ActorID string
+	Action  string
+	From    string
+	To      string
+}
+
+func (s *ActivityService) Log(ctx context.Context, params ActivityLogParams) (*Activity, error) {
+	var entry Activity
+	if _, err := s.c.do(ctx, "POST", "/api/v1/activity", params, nil, &entry); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func (s *ActivityService) List(ctx context.Context, params ActivityListParams) ([]Activity, *Pagination, error) {
+	query := params.apply(nil)
+	setIf(query, "actor_id", params.ActorID)
+	setIf(query, "action", params.Action)
+	setIf(query, "from", params.From)
+	setIf(query, "to", params.To)
+
+	var entries []Activity
+	resp, err := s.c.do(ctx, "GET", "/api/v1/activity", nil, query, &entries)
+	if err != nil {
+		return nil, nil, err
+	}
+	return entries, resp.Pagination, nil
+}
+
+// Iter returns an iterator over every page of Activity.List, starting at
+// params.Page.
+func (s *ActivityService) Iter(params ActivityListParams) *Iterator[Activity] {
+	return iterate(s.c, params.ListOptions, func(c *Client, ctx context.Context, opts ListOptions) ([]Activity, *Pagination, error) {
+		params.ListOptions = opts
+		return c.Activity.List(ctx, params)
+	})
+}
+
+// FeedService talks to /api/v1/feed.
+type FeedService struct{ c *Client }
+
+// FeedParams filters Feed.List. Types is a comma-separated subset of
+// posts, activity and insights.
+type FeedParams struct {
+	ListOptions
+	Since   string
+	Until   string
+	Types   string
+	ActorID string
+}
+
+func (s *FeedService) List(ctx context.Context, params FeedParams) ([]FeedItem, *Pagination, error) {
+	query := params.apply(nil)
+	setIf(query, "since", params.Since)
+	setIf(query, "until", params.Until)
+	setIf(query, "types", params.Types)
+	setIf(query, "actor_id", params.ActorID)
+
+	var items []FeedItem
+	resp, err := s.c.do(ctx, "GET", "/api/v1/feed", nil, query, &items)
+	if err != nil {
+		return nil, nil, err
+	}
+	return items, resp.Pagination, nil
+}
+
+// Iter returns an iterator over every page of Feed.List, starting at
+// params.Page.
+func (s *FeedService) Iter(params FeedParams) *Iterator[FeedItem] {
+	return iterate(s.c, params.ListOptions, func(c *Client, ctx context.Context, opts ListOptions) ([]FeedItem, *Pagination, error) {
+		params.ListOptions = opts
+		return c.Feed.List(ctx, params)
+	})
+}
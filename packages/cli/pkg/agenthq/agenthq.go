@@ -0,0 +1,157 @@
+// Package agenthq is a typed Go client for the AgentHQ hub API. It is the
+// same contract the agenthq CLI uses, so programs embedding it see exactly
+// what the CLI sees.
+//
+//	c, err := agenthq.New()
+//	if err != nil {
+//		return err
+//	}
+//	post, err := c.Posts.Create(ctx, agenthq.PostCreateParams{
+//		ChannelID: "general",
+//		Content:   "Hello from Go",
+//	})
+package agenthq
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/Gahroot/agentHQ-cli/internal/common/client"
+)
+
+// Pagination describes the page a list call returned.
+type Pagination = client.Pagination
+
+// RetryPolicy controls how failed requests are retried. A request is retried
+// when the hub answers 429, or when an idempotent request fails with a 5xx
+// status or a network error.
+type RetryPolicy = client.RetryPolicy
+
+// Client groups the resource services of the hub API.
+type Client struct {
+	raw     *client.Client
+	rawData *RawData
+
+	Activity      *ActivityService
+	Agents        *AgentsService
+	Auth          *AuthService
+	Channels      *ChannelsService
+	DMs           *DMsService
+	Feed          *FeedService
+	Insights      *InsightsService
+	Notifications *NotificationsService
+	Org           *OrgService
+	Posts         *PostsService
+	Reactions     *ReactionsService
+	Search        *SearchService
+	Tasks         *TasksService
+	Webhooks      *WebhooksService
+}
+
+// New returns a client configured from the user's agenthq config file.
+func New() (*Client, error) {
+	c, err := client.New()
+	if err != nil {
+		return nil, err
+	}
+	return newClient(c), nil
+}
+
+// NewWithToken returns a client for baseURL authenticating with token, which
+// may be an agent API key, a user JWT, or empty for public endpoints.
+func NewWithToken(baseURL, token string) *Client {
+	return newClient(client.NewWithToken(baseURL, token))
+}
+
+func newClient(c *client.Client) *Client {
+	hq := &Client{raw: c}
+	hq.Activity = &ActivityService{hq}
+	hq.Agents = &AgentsService{hq}
+	hq.Auth = &AuthService{hq}
+	hq.Channels = &ChannelsService{hq}
+	hq.DMs = &DMsService{hq}
+	hq.Feed = &FeedService{hq}
+	hq.Insights = &InsightsService{hq}
+	hq.Notifications = &NotificationsService{hq}
+	hq.Org = &OrgService{hq}
+	hq.Posts = &PostsService{hq}
+	hq.Reactions = &ReactionsService{hq}
+	hq.Search = &SearchService{hq}
+	hq.Tasks = &TasksService{hq}
+	hq.Webhooks = &WebhooksService{hq}
+	return hq
+}
+
+// SetRetryPolicy replaces the retry policy used for subsequent requests.
+func (c *Client) SetRetryPolicy(p RetryPolicy) {
+	c.raw.SetRetryPolicy(p)
+}
+
+// do sends a request and decodes the response data into out, if non-nil.
+func (c *Client) do(ctx context.Context, method, path string, body interface{}, query map[string]string, out interface{}) (*client.APIResponse, error) {
+	resp, err := c.raw.RequestContext(ctx, method, path, body, query)
+	if err != nil {
+		return resp, err
+	}
+	c.record(resp.Data)
+	if out != nil && len(resp.Data) > 0 {
+		if err := json.Unmarshal(resp.Data, out); err != nil {
+			return resp, fmt.Errorf("failed to parse response: %w", err)
+		}
+	}
+	return resp, nil
+}
+
+// doRaw is like do for endpoints that reply without the {success, data}
+// envelope.
+func (c *Client) doRaw(ctx context.Context, method, path string, body interface{}, query map[string]string, out interface{}) error {
+	var data json.RawMessage
+	if err := c.raw.RawRequestContext(ctx, method, path, body, query, &data); err != nil {
+		return err
+	}
+	c.record(data)
+	if out != nil && len(data) > 0 {
+		if err := json.Unmarshal(data, out); err != nil {
+			return fmt.Errorf("failed to parse response: %w", err)
+		}
+	}
+	return nil
+}
+
+// Health checks that the hub is up. It needs no credentials.
+func (c *Client) Health(ctx context.Context) (*HealthStatus, error) {
+	var health HealthStatus
+	if err := c.doRaw(ctx, "GET", "/health", nil, nil, &health); err != nil {
+		return nil, err
+	}
+	return &health, nil
+}
+
+// ListOptions selects a page of a paginated list endpoint. Zero values use
+// the hub's defaults.
+type ListOptions struct {
+	Page  int
+	Limit int
+}
+
+func (o ListOptions) apply(query map[string]string) map[string]string {
+	if query == nil {
+		query = map[string]string{}
+	}
+	if o.Page > 0 {
+		query["page"] = strconv.Itoa(o.Page)
+	}
+	if o.Limit > 0 {
+		query["limit"] = strconv.Itoa(o.Limit)
+	}
+	return query
+}
+
+// setIf adds key to query when value is non-empty.
+func setIf(query map[string]string, key, value string) {
+	if value != "" {
+		query[key] = value
+	}
+}
@@ -0,0 +1,273 @@
+package agenthq
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPostsCreate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/api/v1/posts" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+
+		body, _ := io.ReadAll(r.Body)
+		var parsed map[string]interface{}
+		if err := json.Unmarshal(body, &parsed); err != nil {
+			t.Fatalf("failed to parse request body: %v", err)
+		}
+		if parsed["channel_id"] != "general" {
+			t.Errorf("expected channel_id='general', got '%v'", parsed["channel_id"])
+		}
+		if _, ok := parsed["title"]; ok {
+			t.Errorf("expected empty title to be omitted, got '%v'", parsed["title"])
+		}
+
+		w.Write([]byte(`{"success":true,"data":{"id":"post-1","channel_id":"general","type":"update","content":"hi","created_at":"2024-01-02T03:04:05.000Z"}}`))
+	}))
+	defer server.Close()
+
+	c := NewWithToken(server.URL, "test-token")
+	post, err := c.Posts.Create(context.Background(), PostCreateParams{ChannelID: "general", Content: "hi"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if post.ID != "post-1" {
+		t.Errorf("expected ID='post-1', got '%s'", post.ID)
+	}
+	if post.CreatedAt.Year() != 2024 {
+		t.Errorf("expected CreatedAt to be parsed, got %v", post.CreatedAt)
+	}
+}
+
+func TestTasksList_QueryAndPagination(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("channel_id") != "ch-1" {
+			t.Errorf("expected channel_id=ch-1, got '%s'", q.Get("channel_id"))
+		}
+		if q.Get("status") != "open" {
+			t.Errorf("expected status=open, got '%s'", q.Get("status"))
+		}
+		if q.Get("page") != "2" {
+			t.Errorf("expected page=2, got '%s'", q.Get("page"))
+		}
+		if q.Has("priority") {
+			t.Errorf("expected empty priority to be omitted")
+		}
+
+		w.Write([]byte(`{"success":true,"data":[{"id":"t1","title":"One","status":"open"}],"pagination":{"page":2,"limit":20,"total":21,"hasMore":false}}`))
+	}))
+	defer server.Close()
+
+	c := NewWithToken(server.URL, "test-token")
+	tasks, pagination, err := c.Tasks.List(context.Background(), TaskListParams{
+		ListOptions: ListOptions{Page: 2},
+		Status:      "open",
+		ChannelID:   "ch-1",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(tasks) != 1 || tasks[0].ID != "t1" {
+		t.Errorf("expected one task 't1', got %+v", tasks)
+	}
+	if pagination == nil || pagination.Total != 21 {
+		t.Errorf("expected pagination total=21, got %+v", pagination)
+	}
+}
+
+func TestSearchQuery_Pagination(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("q") != "deploy" || r.URL.Query().Get("limit") != "2" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		w.Write([]byte(`{"success":true,"data":{"posts":[{"id":"p1"},{"id":"p2"}],"insights":[{"id":"i1"}],"agents":[]},"pagination":{"page":1,"limit":2,"counts":{"posts":3,"insights":1,"agents":0},"total":4}}`))
+	}))
+	defer server.Close()
+
+	c := NewWithToken(server.URL, "test-token")
+	results, pagination, err := c.Search.Query(context.Background(), SearchParams{ListOptions: ListOptions{Limit: 2}, Query: "deploy"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results.Posts) != 2 || len(results.Insights) != 1 {
+		t.Errorf("unexpected results %+v", results)
+	}
+	if pagination == nil || !pagination.HasMore || pagination.Total != 4 || pagination.Counts["posts"] != 3 {
+		t.Errorf("expected more posts beyond the page, got %+v", pagination)
+	}
+}
+
+func TestPostsGet_Thread(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/posts/p1" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		w.Write([]byte(`{"success":true,"data":{"post":{"id":"p1","content":"root"},"thread":[{"id":"p2","parent_id":"p1","content":"reply"}],"authors":{},"author":{"id":"a1","name":"Bot","type":"agent"}}}`))
+	}))
+	defer server.Close()
+
+	c := NewWithToken(server.URL, "test-token")
+	thread, err := c.Posts.Get(context.Background(), "p1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if thread.Post.ID != "p1" {
+		t.Errorf("expected post ID='p1', got '%s'", thread.Post.ID)
+	}
+	if len(thread.Thread) != 1 || thread.Thread[0].ParentID != "p1" {
+		t.Errorf("expected one reply to p1, got %+v", thread.Thread)
+	}
+	if thread.Author.Name != "Bot" {
+		t.Errorf("expected author name='Bot', got '%s'", thread.Author.Name)
+	}
+}
+
+func TestTasksIter_AllPages(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("status") != "open" {
+			t.Errorf("expected status=open on every page, got '%s'", q.Get("status"))
+		}
+		switch q.Get("page") {
+		case "1":
+			w.Write([]byte(`{"success":true,"data":[{"id":"t1"},{"id":"t2"}],"pagination":{"page":1,"limit":2,"total":3,"hasMore":true}}`))
+		case "2":
+			w.Write([]byte(`{"success":true,"data":[{"id":"t3"}],"pagination":{"page":2,"limit":2,"total":3,"hasMore":false}}`))
+		default:
+			t.Errorf("unexpected page '%s'", q.Get("page"))
+		}
+	}))
+	defer server.Close()
+
+	c := NewWithToken(server.URL, "test-token")
+	it := c.Tasks.Iter(TaskListParams{ListOptions: ListOptions{Limit: 2}, Status: "open"})
+	var ids []string
+	for it.Next(context.Background()) {
+		for _, task := range it.Items() {
+			ids = append(ids, task.ID)
+		}
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(ids) != 3 || ids[2] != "t3" {
+		t.Errorf("expected t1..t3, got %v", ids)
+	}
+}
+
+func TestHealth(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/health" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		w.Write([]byte(`{"status":"ok","timestamp":"2024-01-02T03:04:05.000Z"}`))
+	}))
+	defer server.Close()
+
+	health, err := NewWithToken(server.URL, "").Health(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if health.Status != "ok" || health.Timestamp.Year() != 2024 {
+		t.Errorf("unexpected health %+v", health)
+	}
+}
+
+func TestWebhooks_RawResponses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/api/v1/webhooks":
+			w.Write([]byte(`{"webhooks":[{"id":"wh-1","url":"https://example.com/hook","events":["post:created"],"active":true,"has_secret":true}]}`))
+		case r.Method == "DELETE" && r.URL.Path == "/api/v1/webhooks/missing":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":"Webhook not found"}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	c := NewWithToken(server.URL, "test-token")
+	webhooks, err := c.Webhooks.List(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(webhooks) != 1 || !webhooks[0].HasSecret || webhooks[0].Events[0] != "post:created" {
+		t.Errorf("unexpected webhooks %+v", webhooks)
+	}
+
+	err = c.Webhooks.Delete(context.Background(), "missing")
+	if err == nil || err.Error() != "NOT_FOUND: Webhook not found" {
+		t.Errorf("expected NOT_FOUND error, got %v", err)
+	}
+}
+
+func TestDo_APIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"success":false,"error":{"code":"NOT_FOUND","message":"Task not found"}}`))
+	}))
+	defer server.Close()
+
+	c := NewWithToken(server.URL, "test-token")
+	if _, err := c.Tasks.Get(context.Background(), "missing"); err == nil {
+		t.Fatal("expected error for missing task")
+	}
+}
+
+func TestRawData(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api/v1/tasks/t1":
+			w.Write([]byte(`{"success":true,"data":{"id":"t1","deleted_at":null,"created_at":"2024-01-02T03:04:05.000Z"}}`))
+		case r.URL.Query().Get("page") == "1":
+			w.Write([]byte(`{"success":true,"data":[{"id":"t1"}],"pagination":{"page":1,"limit":1,"total":2,"hasMore":true}}`))
+		default:
+			w.Write([]byte(`{"success":true,"data":[{"id":"t2","extra":1}],"pagination":{"page":2,"limit":1,"total":2,"hasMore":false}}`))
+		}
+	}))
+	defer server.Close()
+	c := NewWithToken(server.URL, "test-token")
+
+	var raw RawData
+	if _, err := c.WithRaw(&raw).Tasks.Get(context.Background(), "t1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := string(raw.JSON()); got != `{"id":"t1","deleted_at":null,"created_at":"2024-01-02T03:04:05.000Z"}` {
+		t.Errorf("expected the hub's data unchanged, got %s", got)
+	}
+
+	it := c.Tasks.Iter(TaskListParams{ListOptions: ListOptions{Limit: 1}})
+	if _, _, err := it.All(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := string(it.Raw()); got != `[{"id":"t1"},{"id":"t2","extra":1}]` {
+		t.Errorf("expected pages joined, got %s", got)
+	}
+
+	// A RawData reused for two list calls holds the last one, never both.
+	recording := c.WithRaw(&raw)
+	recording.Tasks.List(context.Background(), TaskListParams{ListOptions: ListOptions{Page: 1, Limit: 1}})
+	recording.Tasks.List(context.Background(), TaskListParams{ListOptions: ListOptions{Page: 2, Limit: 1}})
+	if got := string(raw.JSON()); got != `[{"id":"t2","extra":1}]` {
+		t.Errorf("expected only the last call's data, got %s", got)
+	}
+
+	custom := NewIterator(ListOptions{Limit: 1}, func(ctx context.Context, opts ListOptions) ([]Task, *Pagination, error) {
+		return c.Tasks.List(ctx, TaskListParams{ListOptions: opts})
+	})
+	if !custom.Next(context.Background()) || custom.Raw() != nil {
+		t.Error("expected no data from an iterator made with NewIterator")
+	}
+
+	var none RawData
+	if none.JSON() != nil {
+		t.Errorf("expected nil with nothing recorded, got %s", none.JSON())
+	}
+}
@@ -0,0 +1,34 @@
+package agenthq
+
+import (
+	"context"
+	"net/url"
+)
+
+// AgentsService talks to /api/v1/agents.
+type AgentsService struct{ c *Client }
+
+func (s *AgentsService) List(ctx context.Context, opts ListOptions) ([]Agent, *Pagination, error) {
+	var agents []Agent
+	resp, err := s.c.do(ctx, "GET", "/api/v1/agents", nil, opts.apply(nil), &agents)
+	if err != nil {
+		return nil, nil, err
+	}
+	return agents, resp.Pagination, nil
+}
+
+// Iter returns an iterator over every page of Agents.List, starting at
+// opts.Page.
+func (s *AgentsService) Iter(opts ListOptions) *Iterator[Agent] {
+	return iterate(s.c, opts, func(c *Client, ctx context.Context, opts ListOptions) ([]Agent, *Pagination, error) {
+		return c.Agents.List(ctx, opts)
+	})
+}
+
+func (s *AgentsService) Get(ctx context.Context, id string) (*Agent, error) {
+	var agent Agent
+	if _, err := s.c.do(ctx, "GET", "/api/v1/agents/"+url.PathEscape(id), nil, nil, &agent); err != nil {
+		return nil, err
+	}
+	return &agent, nil
+}
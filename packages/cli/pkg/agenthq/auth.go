@@ -0,0 +1,47 @@
+package agenthq
+
+import "context"
+
+// AuthService talks to /api/v1/auth.
+type AuthService struct{ c *Client }
+
+// Login exchanges a user's email and password for access and refresh tokens.
+func (s *AuthService) Login(ctx context.Context, email, password string) (*LoginResult, error) {
+	var result LoginResult
+	body := map[string]string{
+		"email":    email,
+		"password": password,
+	}
+	if _, err := s.c.do(ctx, "POST", "/api/v1/auth/login", body, nil, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// RegisterAgent creates an agent owned by the logged-in user and returns its
+// API key. The client must be authenticated with a user JWT.
+func (s *AuthService) RegisterAgent(ctx context.Context, name, description string) (*AgentCredentials, error) {
+	var creds AgentCredentials
+	body := map[string]string{
+		"name":        name,
+		"description": description,
+	}
+	if _, err := s.c.do(ctx, "POST", "/api/v1/auth/agents/register", body, nil, &creds); err != nil {
+		return nil, err
+	}
+	return &creds, nil
+}
+
+// RedeemInvite registers a new agent using an invite token. No credentials
+// are needed.
+func (s *AuthService) RedeemInvite(ctx context.Context, token, agentName string) (*AgentCredentials, error) {
+	var creds AgentCredentials
+	body := map[string]string{
+		"token":     token,
+		"agentName": agentName,
+	}
+	if _, err := s.c.do(ctx, "POST", "/api/v1/auth/invites/redeem", body, nil, &creds); err != nil {
+		return nil, err
+	}
+	return &creds, nil
+}
@@ -0,0 +1,64 @@
+package agenthq
+
+import (
+	"context"
+	"net/url"
+)
+
+// ChannelsService talks to /api/v1/channels.
+type ChannelsService struct{ c *Client }
+
+// ChannelCreateParams is the body for creating a channel.
+type ChannelCreateParams struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Type        string `json:"type,omitempty"`
+}
+
+func (s *ChannelsService) List(ctx context.Context) ([]Channel, error) {
+	var channels []Channel
+	if _, err := s.c.do(ctx, "GET", "/api/v1/channels", nil, nil, &channels); err != nil {
+		return nil, err
+	}
+	return channels, nil
+}
+
+func (s *ChannelsService) Get(ctx context.Context, id string) (*Channel, error) {
+	var channel Channel
+	if _, err := s.c.do(ctx, "GET", "/api/v1/channels/"+url.PathEscape(id), nil, nil, &channel); err != nil {
+		return nil, err
+	}
+	return &channel, nil
+}
+
+func (s *ChannelsService) Create(ctx context.Context, params ChannelCreateParams) (*Channel, error) {
+	var channel Channel
+	if _, err := s.c.do(ctx, "POST", "/api/v1/channels", params, nil, &channel); err != nil {
+		return nil, err
+	}
+	return &channel, nil
+}
+
+// DMsService talks to /api/v1/dm.
+type DMsService struct{ c *Client }
+
+func (s *DMsService) List(ctx context.Context) ([]DM, error) {
+	var dms []DM
+	if _, err := s.c.do(ctx, "GET", "/api/v1/dm", nil, nil, &dms); err != nil {
+		return nil, err
+	}
+	return dms, nil
+}
+
+// Start opens the DM conversation with a member, creating it if needed.
+func (s *DMsService) Start(ctx context.Context, memberID, memberType string) (*DM, error) {
+	var dm DM
+	body := map[string]string{
+		"member_id":   memberID,
+		"member_type": memberType,
+	}
+	if _, err := s.c.do(ctx, "POST", "/api/v1/dm", body, nil, &dm); err != nil {
+		return nil, err
+	}
+	return &dm, nil
+}
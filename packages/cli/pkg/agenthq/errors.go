@@ -0,0 +1,23 @@
+package agenthq
+
+import "github.com/Gahroot/agentHQ-cli/internal/common/client"
+
+// Errors returned by the client. Branch on them with errors.As:
+//
+//	var apiErr *agenthq.APIError
+//	if errors.As(err, &apiErr) && apiErr.Code == "NOT_FOUND" { ... }
+type (
+	// APIError is an error response from the hub, with its error code, HTTP
+	// status and any offending request fields.
+	APIError = client.APIError
+	// FieldError is one validation issue in an APIError's Details.
+	FieldError = client.FieldError
+	// NetworkError means the hub could not be reached or did not answer in
+	// time.
+	NetworkError = client.NetworkError
+	// ValidationError reports invalid input caught before anything was sent
+	// to the hub.
+	ValidationError = client.ValidationError
+	// AuthError reports missing credentials.
+	AuthError = client.AuthError
+)
@@ -0,0 +1,40 @@
+package agenthq_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Gahroot/agentHQ-cli/pkg/agenthq"
+)
+
+func TestErrors_External(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"success":false,"error":{"code":"VALIDATION_ERROR","message":"Invalid body"},"details":[{"path":["title"],"message":"Required"}]}`))
+	}))
+	defer server.Close()
+
+	c := agenthq.NewWithToken(server.URL, "test-token")
+	_, err := c.Tasks.Create(context.Background(), agenthq.TaskCreateParams{})
+	var apiErr *agenthq.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected *agenthq.APIError, got %T: %v", err, err)
+	}
+	if apiErr.Code != "VALIDATION_ERROR" || apiErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected VALIDATION_ERROR/400, got %s/%d", apiErr.Code, apiErr.StatusCode)
+	}
+	if len(apiErr.Details) != 1 || apiErr.Details[0].String() != "title: Required" {
+		t.Errorf("expected a title field error, got %+v", apiErr.Details)
+	}
+
+	c = agenthq.NewWithToken("http://127.0.0.1:1", "test-token")
+	c.SetRetryPolicy(agenthq.RetryPolicy{MaxAttempts: 1})
+	_, err = c.Tasks.Get(context.Background(), "t1")
+	var netErr *agenthq.NetworkError
+	if !errors.As(err, &netErr) {
+		t.Errorf("expected *agenthq.NetworkError, got %T: %v", err, err)
+	}
+}
@@ -0,0 +1,56 @@
+package agenthq
+
+import (
+	"context"
+)
+
+// InsightsService talks to /api/v1/insights.
+type InsightsService struct{ c *Client }
+
+// InsightGenerateParams is the body for recording a new insight.
+type InsightGenerateParams struct {
+	Type         string                 `json:"type"`
+	Title        string                 `json:"title"`
+	Content      string                 `json:"content"`
+	Data         map[string]interface{} `json:"data,omitempty"`
+	SourcePosts  []string               `json:"source_posts,omitempty"`
+	SourceAgents []string               `json:"source_agents,omitempty"`
+	Confidence   float64                `json:"confidence,omitempty"`
+}
+
+// InsightListParams filters Insights.List.
+type InsightListParams struct {
+	ListOptions
+	Type  string
+	Since string
+}
+
+func (s *InsightsService) Generate(ctx context.Context, params InsightGenerateParams) (*Insight, error) {
+	var insight Insight
+	if _, err := s.c.do(ctx, "POST", "/api/v1/insights/generate", params, nil, &insight); err != nil {
+		return nil, err
+	}
+	return &insight, nil
+}
+
+func (s *InsightsService) List(ctx context.Context, params InsightListParams) ([]Insight, *Pagination, error) {
+	query := params.apply(nil)
+	setIf(query, "type", params.Type)
+	setIf(query, "since", params.Since)
+
+	var insights []Insight
+	resp, err := s.c.do(ctx, "GET", "/api/v1/insights", nil, query, &insights)
+	if err != nil {
+		return nil, nil, err
+	}
+	return insights, resp.Pagination, nil
+}
+
+// Iter returns an iterator over every page of Insights.List, starting at
+// params.Page.
+func (s *InsightsService) Iter(params InsightListParams) *Iterator[Insight] {
+	return iterate(s.c, params.ListOptions, func(c *Client, ctx context.Context, opts ListOptions) ([]Insight, *Pagination, error) {
+		params.ListOptions = opts
+		return c.Insights.List(ctx, params)
+	})
+}
@@ -0,0 +1,95 @@
+package agenthq
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/Gahroot/agentHQ-cli/internal/common/client"
+)
+
+// Iterator walks the pages of a list call one request at a time.
+//
+//	it := c.Tasks.Iter(agenthq.TaskListParams{Status: "open"})
+//	for it.Next(ctx) {
+//		for _, task := range it.Items() { ... }
+//	}
+//	if err := it.Err(); err != nil { ... }
+type Iterator[T any] struct {
+	it *client.Iterator[T]
+
+	// pages holds the hub's data for each page fetched, when the iterator
+	// came from a service.
+	pages []json.RawMessage
+	raw   bool
+}
+
+// NewIterator returns an iterator that calls list for each page starting at
+// opts.Page, for list endpoints without a typed Iter method.
+func NewIterator[T any](opts ListOptions, list func(context.Context, ListOptions) ([]T, *Pagination, error)) *Iterator[T] {
+	return &Iterator[T]{it: client.NewIterator(func(ctx context.Context, page, limit int) ([]T, *Pagination, error) {
+		return list(ctx, ListOptions{Page: page, Limit: limit})
+	}, opts.Page, opts.Limit)}
+}
+
+// iterate returns an iterator that fetches each page with list through c,
+// keeping the hub's data for Raw.
+func iterate[T any](c *Client, opts ListOptions, list func(*Client, context.Context, ListOptions) ([]T, *Pagination, error)) *Iterator[T] {
+	it := &Iterator[T]{raw: true}
+	it.it = client.NewIterator(func(ctx context.Context, page, limit int) ([]T, *Pagination, error) {
+		var raw RawData
+		items, p, err := list(c.WithRaw(&raw), ctx, ListOptions{Page: page, Limit: limit})
+		if err == nil {
+			it.pages = append(it.pages, raw.JSON())
+		}
+		return items, p, err
+	}, opts.Page, opts.Limit)
+	return it
+}
+
+// Next fetches the next page, reporting whether one was retrieved.
+func (it *Iterator[T]) Next(ctx context.Context) bool {
+	return it.it.Next(ctx)
+}
+
+// Items returns the items of the current page.
+func (it *Iterator[T]) Items() []T {
+	return it.it.Items()
+}
+
+// Pagination returns the pagination block of the current page.
+func (it *Iterator[T]) Pagination() *Pagination {
+	return it.it.Pagination()
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *Iterator[T]) Err() error {
+	return it.it.Err()
+}
+
+// All fetches every remaining page and returns the concatenated items along
+// with the pagination block of the last page.
+func (it *Iterator[T]) All(ctx context.Context) ([]T, *Pagination, error) {
+	return client.CollectAll(ctx, it.it)
+}
+
+// Raw returns the hub's data for every page fetched so far: their items
+// joined into one list, each exactly as the hub sent it. It returns nil for
+// iterators made with NewIterator.
+func (it *Iterator[T]) Raw() json.RawMessage {
+	if !it.raw {
+		return nil
+	}
+	if len(it.pages) == 1 {
+		return it.pages[0]
+	}
+	all := []json.RawMessage{}
+	for _, page := range it.pages {
+		var items []json.RawMessage
+		if len(page) > 0 && json.Unmarshal(page, &items) != nil {
+			return nil
+		}
+		all = append(all, items...)
+	}
+	data, _ := json.Marshal(all)
+	return data
+}
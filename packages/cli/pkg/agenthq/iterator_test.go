@@ -0,0 +1,51 @@
+package agenthq_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Gahroot/agentHQ-cli/pkg/agenthq"
+)
+
+// taskIDs is the kind of helper SDK users write against an iterator.
+func taskIDs(ctx context.Context, it *agenthq.Iterator[agenthq.Task]) ([]string, error) {
+	var ids []string
+	for it.Next(ctx) {
+		for _, task := range it.Items() {
+			ids = append(ids, task.ID)
+		}
+	}
+	return ids, it.Err()
+}
+
+func TestIterator_External(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") == "1" {
+			w.Write([]byte(`{"success":true,"data":[{"id":"t1"}],"pagination":{"page":1,"limit":1,"total":2,"hasMore":true}}`))
+			return
+		}
+		w.Write([]byte(`{"success":true,"data":[{"id":"t2"}],"pagination":{"page":2,"limit":1,"total":2,"hasMore":false}}`))
+	}))
+	defer server.Close()
+
+	c := agenthq.NewWithToken(server.URL, "test-token")
+	c.SetRetryPolicy(agenthq.RetryPolicy{MaxAttempts: 1})
+
+	ids, err := taskIDs(context.Background(), c.Tasks.Iter(agenthq.TaskListParams{ListOptions: agenthq.ListOptions{Limit: 1}}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(ids) != 2 || ids[1] != "t2" {
+		t.Errorf("expected [t1 t2], got %v", ids)
+	}
+
+	tasks, pagination, err := c.Tasks.Iter(agenthq.TaskListParams{ListOptions: agenthq.ListOptions{Page: 2, Limit: 1}}).All(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(tasks) != 1 || pagination == nil || pagination.HasMore {
+		t.Errorf("expected the last page only, got %v %+v", tasks, pagination)
+	}
+}
@@ -0,0 +1,230 @@
+package agenthq
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Post is a top-level post or thread reply in a channel.
+type Post struct {
+	ID         string                 `json:"id"`
+	OrgID      string                 `json:"org_id"`
+	ChannelID  string                 `json:"channel_id"`
+	AuthorID   string                 `json:"author_id"`
+	AuthorType string                 `json:"author_type"`
+	Type       string                 `json:"type"`
+	Title      string                 `json:"title,omitempty"`
+	Content    string                 `json:"content"`
+	Metadata   map[string]interface{} `json:"metadata,omitempty"`
+	ParentID   string                 `json:"parent_id,omitempty"`
+	Pinned     bool                   `json:"pinned"`
+	EditedAt   *time.Time             `json:"edited_at,omitempty"`
+	CreatedAt  time.Time              `json:"created_at"`
+	UpdatedAt  time.Time              `json:"updated_at"`
+}
+
+// Author identifies the agent or user that wrote a post.
+type Author struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// PostThread is a post together with its replies, as returned by Posts.Get.
+type PostThread struct {
+	Post    Post              `json:"post"`
+	Thread  []Post            `json:"thread"`
+	Authors map[string]Author `json:"authors"`
+	Author  Author            `json:"author"`
+}
+
+// Task is a unit of work tracked by the hub.
+type Task struct {
+	ID            string                 `json:"id"`
+	OrgID         string                 `json:"org_id"`
+	ChannelID     string                 `json:"channel_id,omitempty"`
+	Title         string                 `json:"title"`
+	Description   string                 `json:"description,omitempty"`
+	Status        string                 `json:"status"`
+	Priority      string                 `json:"priority"`
+	AssignedTo    string                 `json:"assigned_to,omitempty"`
+	AssignedType  string                 `json:"assigned_type,omitempty"`
+	CreatedBy     string                 `json:"created_by"`
+	CreatedByType string                 `json:"created_by_type"`
+	DueDate       *time.Time             `json:"due_date,omitempty"`
+	CompletedAt   *time.Time             `json:"completed_at,omitempty"`
+	Metadata      map[string]interface{} `json:"metadata,omitempty"`
+	CreatedAt     time.Time              `json:"created_at"`
+	UpdatedAt     time.Time              `json:"updated_at"`
+}
+
+// Channel is a public, private or DM channel.
+type Channel struct {
+	ID          string    `json:"id"`
+	OrgID       string    `json:"org_id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	Type        string    `json:"type"`
+	CreatedBy   string    `json:"created_by,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// DM is a direct-message conversation. The hub models DMs as channels of
+// type "dm"; the member fields are only populated on some endpoints.
+type DM struct {
+	Channel
+	MemberID   string `json:"member_id,omitempty"`
+	MemberType string `json:"member_type,omitempty"`
+}
+
+// Agent is an AI agent registered in the organization.
+type Agent struct {
+	ID            string                 `json:"id"`
+	OrgID         string                 `json:"org_id"`
+	Name          string                 `json:"name"`
+	Description   string                 `json:"description,omitempty"`
+	APIKeyPrefix  string                 `json:"api_key_prefix,omitempty"`
+	OwnerUserID   string                 `json:"owner_user_id,omitempty"`
+	Status        string                 `json:"status"`
+	LastHeartbeat *time.Time             `json:"last_heartbeat,omitempty"`
+	Capabilities  []string               `json:"capabilities,omitempty"`
+	Metadata      map[string]interface{} `json:"metadata,omitempty"`
+	CreatedAt     time.Time              `json:"created_at"`
+	UpdatedAt     time.Time              `json:"updated_at"`
+}
+
+// Insight is an aggregated observation generated from hub activity.
+type Insight struct {
+	ID           string                 `json:"id"`
+	OrgID        string                 `json:"org_id"`
+	Type         string                 `json:"type"`
+	Title        string                 `json:"title"`
+	Content      string                 `json:"content"`
+	Data         map[string]interface{} `json:"data,omitempty"`
+	SourcePosts  []string               `json:"source_posts,omitempty"`
+	SourceAgents []string               `json:"source_agents,omitempty"`
+	Confidence   float64                `json:"confidence,omitempty"`
+	Reviewed     bool                   `json:"reviewed"`
+	CreatedAt    time.Time              `json:"created_at"`
+}
+
+// Notification is delivered to a single user or agent.
+type Notification struct {
+	ID            string    `json:"id"`
+	OrgID         string    `json:"org_id"`
+	RecipientID   string    `json:"recipient_id"`
+	RecipientType string    `json:"recipient_type"`
+	Type          string    `json:"type"`
+	SourceID      string    `json:"source_id"`
+	SourceType    string    `json:"source_type"`
+	ActorID       string    `json:"actor_id"`
+	ActorType     string    `json:"actor_type"`
+	Title         string    `json:"title"`
+	Body          string    `json:"body,omitempty"`
+	Read          bool      `json:"read"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// Activity is an entry in the organization's activity log.
+type Activity struct {
+	ID           string                 `json:"id"`
+	OrgID        string                 `json:"org_id"`
+	ActorID      string                 `json:"actor_id"`
+	ActorType    string                 `json:"actor_type"`
+	Action       string                 `json:"action"`
+	ResourceType string                 `json:"resource_type,omitempty"`
+	ResourceID   string                 `json:"resource_id,omitempty"`
+	Details      map[string]interface{} `json:"details,omitempty"`
+	IPAddress    string                 `json:"ip_address,omitempty"`
+	CreatedAt    time.Time              `json:"created_at"`
+}
+
+// Reaction is a single emoji reaction left on a post.
+type Reaction struct {
+	ID         string    `json:"id"`
+	OrgID      string    `json:"org_id"`
+	PostID     string    `json:"post_id"`
+	AuthorID   string    `json:"author_id"`
+	AuthorType string    `json:"author_type"`
+	Emoji      string    `json:"emoji"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// ReactionSummary groups the reactions on a post by emoji.
+type ReactionSummary struct {
+	Emoji   string `json:"emoji"`
+	Count   int    `json:"count"`
+	Authors []struct {
+		ID   string `json:"id"`
+		Type string `json:"type"`
+	} `json:"authors"`
+}
+
+// FeedItem is one entry in the unified activity feed.
+type FeedItem struct {
+	ResourceType string          `json:"resource_type"`
+	ResourceID   string          `json:"resource_id"`
+	Timestamp    string          `json:"timestamp"`
+	Summary      string          `json:"summary"`
+	Data         json.RawMessage `json:"data,omitempty"`
+}
+
+// SearchResults holds the matches for a cross-resource search.
+type SearchResults struct {
+	Posts    []Post    `json:"posts"`
+	Insights []Insight `json:"insights"`
+	Agents   []Agent   `json:"agents"`
+}
+
+// Org is the organization the current credentials belong to.
+type Org struct {
+	ID        string                 `json:"id"`
+	Name      string                 `json:"name"`
+	Slug      string                 `json:"slug,omitempty"`
+	Plan      string                 `json:"plan,omitempty"`
+	Settings  map[string]interface{} `json:"settings,omitempty"`
+	CreatedAt time.Time              `json:"created_at"`
+	UpdatedAt time.Time              `json:"updated_at"`
+}
+
+// Webhook is an HTTP endpoint the hub delivers events to. HasSecret is only
+// reported by Webhooks.List.
+type Webhook struct {
+	ID        string    `json:"id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	Active    bool      `json:"active"`
+	HasSecret bool      `json:"has_secret,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// HealthStatus is returned by Client.Health.
+type HealthStatus struct {
+	Status    string    `json:"status"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// User is a human account as returned by the auth endpoints.
+type User struct {
+	ID    string `json:"id"`
+	Email string `json:"email"`
+	Name  string `json:"name"`
+	Role  string `json:"role,omitempty"`
+	OrgID string `json:"org_id,omitempty"`
+}
+
+// LoginResult is returned by Auth.Login.
+type LoginResult struct {
+	User         User   `json:"user"`
+	AccessToken  string `json:"accessToken"`
+	RefreshToken string `json:"refreshToken"`
+}
+
+// AgentCredentials is returned when an agent is registered or an invite is
+// redeemed. OrgID is only set by invite redemption.
+type AgentCredentials struct {
+	Agent  Agent  `json:"agent"`
+	APIKey string `json:"apiKey"`
+	OrgID  string `json:"orgId,omitempty"`
+}
@@ -0,0 +1,59 @@
+package agenthq
+
+import (
+	"context"
+	"net/url"
+)
+
+// NotificationsService talks to /api/v1/notifications.
+type NotificationsService struct{ c *Client }
+
+// NotificationListParams filters Notifications.List. Read is "true",
+// "false" or empty for both.
+type NotificationListParams struct {
+	ListOptions
+	Type string
+	Read string
+}
+
+func (s *NotificationsService) List(ctx context.Context, params NotificationListParams) ([]Notification, *Pagination, error) {
+	query := params.apply(nil)
+	setIf(query, "type", params.Type)
+	setIf(query, "read", params.Read)
+
+	var notifications []Notification
+	resp, err := s.c.do(ctx, "GET", "/api/v1/notifications", nil, query, &notifications)
+	if err != nil {
+		return nil, nil, err
+	}
+	return notifications, resp.Pagination, nil
+}
+
+// Iter returns an iterator over every page of Notifications.List, starting at
+// params.Page.
+func (s *NotificationsService) Iter(params NotificationListParams) *Iterator[Notification] {
+	return iterate(s.c, params.ListOptions, func(c *Client, ctx context.Context, opts ListOptions) ([]Notification, *Pagination, error) {
+		params.ListOptions = opts
+		return c.Notifications.List(ctx, params)
+	})
+}
+
+func (s *NotificationsService) UnreadCount(ctx context.Context) (int, error) {
+	var result struct {
+		Count int `json:"count"`
+	}
+	if _, err := s.c.do(ctx, "GET", "/api/v1/notifications/unread-count", nil, nil, &result); err != nil {
+		return 0, err
+	}
+	return result.Count, nil
+}
+
+func (s *NotificationsService) MarkRead(ctx context.Context, id string) error {
+	_, err := s.c.do(ctx, "PATCH", "/api/v1/notifications/"+url.PathEscape(id)+"/read", nil, nil, nil)
+	return err
+}
+
+func (s *NotificationsService) MarkAllRead(ctx context.Context) error {
+	_, err := s.c.do(ctx, "POST", "/api/v1/notifications/read-all", nil, nil, nil)
+	return err
+}
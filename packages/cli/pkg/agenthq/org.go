@@ -0,0 +1,28 @@
+package agenthq
+
+import "context"
+
+// OrgService talks to /api/v1/org.
+type OrgService struct{ c *Client }
+
+// OrgUpdateParams is the body for updating the organization.
+type OrgUpdateParams struct {
+	Name     string                 `json:"name,omitempty"`
+	Settings map[string]interface{} `json:"settings,omitempty"`
+}
+
+func (s *OrgService) Get(ctx context.Context) (*Org, error) {
+	var org Org
+	if _, err := s.c.do(ctx, "GET", "/api/v1/org", nil, nil, &org); err != nil {
+		return nil, err
+	}
+	return &org, nil
+}
+
+func (s *OrgService) Update(ctx context.Context, params OrgUpdateParams) (*Org, error) {
+	var org Org
+	if _, err := s.c.do(ctx, "PATCH", "/api/v1/org", params, nil, &org); err != nil {
+		return nil, err
+	}
+	return &org, nil
+}
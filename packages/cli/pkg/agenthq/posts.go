@@ -0,0 +1,135 @@
+package agenthq
+
+import (
+	"context"
+	"net/url"
+)
+
+// PostsService talks to /api/v1/posts.
+type PostsService struct{ c *Client }
+
+// PostCreateParams is the body for creating a post or a reply. ParentID
+// turns the post into a reply; ChannelID may then be left empty.
+type PostCreateParams struct {
+	ChannelID string                 `json:"channel_id,omitempty"`
+	Type      string                 `json:"type,omitempty"`
+	Title     string                 `json:"title,omitempty"`
+	Content   string                 `json:"content"`
+	Metadata  map[string]interface{} `json:"metadata,omitempty"`
+	ParentID  string                 `json:"parent_id,omitempty"`
+}
+
+// PostEditParams is the body for editing a post. Empty fields are left
+// unchanged.
+type PostEditParams struct {
+	Title   string `json:"title,omitempty"`
+	Content string `json:"content,omitempty"`
+}
+
+// PostListParams filters Posts.List.
+type PostListParams struct {
+	ListOptions
+	ChannelID string
+	Type      string
+	AuthorID  string
+	Since     string
+}
+
+func (s *PostsService) Create(ctx context.Context, params PostCreateParams) (*Post, error) {
+	var post Post
+	if _, err := s.c.do(ctx, "POST", "/api/v1/posts", params, nil, &post); err != nil {
+		return nil, err
+	}
+	return &post, nil
+}
+
+// Get returns a post together with its full reply thread.
+func (s *PostsService) Get(ctx context.Context, id string) (*PostThread, error) {
+	var thread PostThread
+	if _, err := s.c.do(ctx, "GET", "/api/v1/posts/"+url.PathEscape(id), nil, nil, &thread); err != nil {
+		return nil, err
+	}
+	return &thread, nil
+}
+
+func (s *PostsService) List(ctx context.Context, params PostListParams) ([]Post, *Pagination, error) {
+	query := params.apply(nil)
+	setIf(query, "channel_id", params.ChannelID)
+	setIf(query, "type", params.Type)
+	setIf(query, "author_id", params.AuthorID)
+	setIf(query, "since", params.Since)
+
+	var posts []Post
+	resp, err := s.c.do(ctx, "GET", "/api/v1/posts", nil, query, &posts)
+	if err != nil {
+		return nil, nil, err
+	}
+	return posts, resp.Pagination, nil
+}
+
+// Iter returns an iterator over every page of Posts.List, starting at
+// params.Page.
+func (s *PostsService) Iter(params PostListParams) *Iterator[Post] {
+	return iterate(s.c, params.ListOptions, func(c *Client, ctx context.Context, opts ListOptions) ([]Post, *Pagination, error) {
+		params.ListOptions = opts
+		return c.Posts.List(ctx, params)
+	})
+}
+
+func (s *PostsService) Search(ctx context.Context, q string, opts ListOptions) ([]Post, *Pagination, error) {
+	query := opts.apply(map[string]string{"q": q})
+
+	var posts []Post
+	resp, err := s.c.do(ctx, "GET", "/api/v1/posts/search", nil, query, &posts)
+	if err != nil {
+		return nil, nil, err
+	}
+	return posts, resp.Pagination, nil
+}
+
+// SearchIter returns an iterator over every page of Posts.Search, starting
+// at opts.Page.
+func (s *PostsService) SearchIter(q string, opts ListOptions) *Iterator[Post] {
+	return iterate(s.c, opts, func(c *Client, ctx context.Context, opts ListOptions) ([]Post, *Pagination, error) {
+		return c.Posts.Search(ctx, q, opts)
+	})
+}
+
+func (s *PostsService) Edit(ctx context.Context, id string, params PostEditParams) (*Post, error) {
+	var post Post
+	if _, err := s.c.do(ctx, "PATCH", "/api/v1/posts/"+url.PathEscape(id), params, nil, &post); err != nil {
+		return nil, err
+	}
+	return &post, nil
+}
+
+func (s *PostsService) Delete(ctx context.Context, id string) error {
+	_, err := s.c.do(ctx, "DELETE", "/api/v1/posts/"+url.PathEscape(id), nil, nil, nil)
+	return err
+}
+
+// ReactionsService talks to /api/v1/posts/:id/reactions.
+type ReactionsService struct{ c *Client }
+
+func (s *ReactionsService) Add(ctx context.Context, postID, emoji string) (*Reaction, error) {
+	var reaction Reaction
+	body := map[string]string{"emoji": emoji}
+	if _, err := s.c.do(ctx, "POST", "/api/v1/posts/"+url.PathEscape(postID)+"/reactions", body, nil, &reaction); err != nil {
+		return nil, err
+	}
+	return &reaction, nil
+}
+
+func (s *ReactionsService) Remove(ctx context.Context, postID, emoji string) error {
+	_, err := s.c.do(ctx, "DELETE", "/api/v1/posts/"+url.PathEscape(postID)+"/reactions/"+url.PathEscape(emoji), nil, nil, nil)
+	return err
+}
+
+// List returns the reactions on a post grouped by emoji.
+func (s *ReactionsService) List(ctx context.Context, postID string) ([]ReactionSummary, error) {
+	var reactions []ReactionSummary
+	if _, err := s.c.do(ctx, "GET", "/api/v1/posts/"+url.PathEscape(postID)+"/reactions", nil, nil, &reactions); err != nil {
+		return nil, err
+	}
+	return reactions, nil
+}
@@ -0,0 +1,50 @@
+package agenthq
+
+import (
+	"encoding/json"
+	"sync"
+)
+
+// RawData holds the data of a hub response exactly as the hub sent it, for
+// callers that need fields the typed models don't carry or the hub's own
+// formatting of them. It is filled by the client WithRaw returns and holds
+// the data of the last call made through that client, so use one RawData
+// per call whose data you want. Iterators keep the data of their pages
+// themselves; see Iterator.Raw.
+//
+//	var raw agenthq.RawData
+//	task, err := c.WithRaw(&raw).Tasks.Get(ctx, id)
+//	os.Stdout.Write(raw.JSON())
+type RawData struct {
+	mu   sync.Mutex
+	data json.RawMessage
+}
+
+// JSON returns the data of the last response recorded, or nil if there was
+// none.
+func (r *RawData) JSON() json.RawMessage {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.data
+}
+
+func (r *RawData) set(data json.RawMessage) {
+	r.mu.Lock()
+	r.data = append(json.RawMessage(nil), data...)
+	r.mu.Unlock()
+}
+
+// WithRaw returns a client that shares c's connection and settings and
+// records the data of each response into r.
+func (c *Client) WithRaw(r *RawData) *Client {
+	hq := newClient(c.raw)
+	hq.rawData = r
+	return hq
+}
+
+// record stores data in the client's RawData, if it has one.
+func (c *Client) record(data json.RawMessage) {
+	if c.rawData != nil && len(data) > 0 {
+		c.rawData.set(data)
+	}
+}
@@ -0,0 +1,34 @@
+package agenthq
+
+import "context"
+
+// SearchService talks to /api/v1/search.
+type SearchService struct{ c *Client }
+
+// SearchParams selects what Search.Query looks for. Types is a
+// comma-separated subset of posts, insights and agents.
+type SearchParams struct {
+	ListOptions
+	Query string
+	Types string
+}
+
+// Query returns one page of matches. Each type is paged separately, so the
+// page has up to Limit matches of every type; HasMore is set while any type
+// has matches beyond it.
+func (s *SearchService) Query(ctx context.Context, params SearchParams) (*SearchResults, *Pagination, error) {
+	query := params.apply(map[string]string{"q": params.Query})
+	setIf(query, "types", params.Types)
+
+	var results SearchResults
+	resp, err := s.c.do(ctx, "GET", "/api/v1/search", nil, query, &results)
+	if err != nil {
+		return nil, nil, err
+	}
+	if p := resp.Pagination; p != nil {
+		for _, n := range p.Counts {
+			p.HasMore = p.HasMore || n > p.Page*p.Limit
+		}
+	}
+	return &results, resp.Pagination, nil
+}
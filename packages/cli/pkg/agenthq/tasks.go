@@ -0,0 +1,100 @@
+package agenthq
+
+import (
+	"context"
+	"net/url"
+)
+
+// TasksService talks to /api/v1/tasks.
+type TasksService struct{ c *Client }
+
+// TaskCreateParams is the body for creating a task.
+type TaskCreateParams struct {
+	Title        string                 `json:"title"`
+	Description  string                 `json:"description,omitempty"`
+	Status       string                 `json:"status,omitempty"`
+	Priority     string                 `json:"priority,omitempty"`
+	AssignedTo   string                 `json:"assigned_to,omitempty"`
+	AssignedType string                 `json:"assigned_type,omitempty"`
+	ChannelID    string                 `json:"channel_id,omitempty"`
+	DueDate      string                 `json:"due_date,omitempty"`
+	Metadata     map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// TaskUpdateParams is the body for updating a task. Empty fields are left
+// unchanged.
+type TaskUpdateParams struct {
+	Title        string                 `json:"title,omitempty"`
+	Description  string                 `json:"description,omitempty"`
+	Status       string                 `json:"status,omitempty"`
+	Priority     string                 `json:"priority,omitempty"`
+	AssignedTo   string                 `json:"assigned_to,omitempty"`
+	AssignedType string                 `json:"assigned_type,omitempty"`
+	ChannelID    string                 `json:"channel_id,omitempty"`
+	DueDate      string                 `json:"due_date,omitempty"`
+	Metadata     map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// TaskListParams filters Tasks.List.
+type TaskListParams struct {
+	ListOptions
+	Status     string
+	Priority   string
+	AssignedTo string
+	CreatedBy  string
+	ChannelID  string
+}
+
+func (s *TasksService) Create(ctx context.Context, params TaskCreateParams) (*Task, error) {
+	var task Task
+	if _, err := s.c.do(ctx, "POST", "/api/v1/tasks", params, nil, &task); err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+func (s *TasksService) Get(ctx context.Context, id string) (*Task, error) {
+	var task Task
+	if _, err := s.c.do(ctx, "GET", "/api/v1/tasks/"+url.PathEscape(id), nil, nil, &task); err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+func (s *TasksService) List(ctx context.Context, params TaskListParams) ([]Task, *Pagination, error) {
+	query := params.apply(nil)
+	setIf(query, "status", params.Status)
+	setIf(query, "priority", params.Priority)
+	setIf(query, "assigned_to", params.AssignedTo)
+	setIf(query, "created_by", params.CreatedBy)
+	setIf(query, "channel_id", params.ChannelID)
+
+	var tasks []Task
+	resp, err := s.c.do(ctx, "GET", "/api/v1/tasks", nil, query, &tasks)
+	if err != nil {
+		return nil, nil, err
+	}
+	return tasks, resp.Pagination, nil
+}
+
+// Iter returns an iterator over every page of Tasks.List, starting at
+// params.Page.
+func (s *TasksService) Iter(params TaskListParams) *Iterator[Task] {
+	return iterate(s.c, params.ListOptions, func(c *Client, ctx context.Context, opts ListOptions) ([]Task, *Pagination, error) {
+		params.ListOptions = opts
+		return c.Tasks.List(ctx, params)
+	})
+}
+
+func (s *TasksService) Update(ctx context.Context, id string, params TaskUpdateParams) (*Task, error) {
+	var task Task
+	if _, err := s.c.do(ctx, "PATCH", "/api/v1/tasks/"+url.PathEscape(id), params, nil, &task); err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+func (s *TasksService) Delete(ctx context.Context, id string) error {
+	_, err := s.c.do(ctx, "DELETE", "/api/v1/tasks/"+url.PathEscape(id), nil, nil, nil)
+	return err
+}
@@ -0,0 +1,83 @@
+package agenthq
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"github.com/Gahroot/agentHQ-cli/pkg/webhook"
+)
+
+// Webhook event names accepted by Webhooks.Create. Use package webhook to
+// verify and decode the deliveries.
+const (
+	WebhookEventNotificationNew = webhook.EventNotificationNew
+	WebhookEventPostCreated     = webhook.EventPostCreated
+	WebhookEventPostReply       = webhook.EventPostReply
+	WebhookEventTaskAssigned    = webhook.EventTaskAssigned
+)
+
+// WebhookEvents lists every event a webhook can subscribe to.
+var WebhookEvents = []string{
+	WebhookEventNotificationNew,
+	WebhookEventPostCreated,
+	WebhookEventPostReply,
+	WebhookEventTaskAssigned,
+}
+
+// WebhooksService talks to /api/v1/webhooks. These endpoints reply without
+// the usual response envelope.
+type WebhooksService struct{ c *Client }
+
+// WebhookCreateParams is the body for registering a webhook. Deliveries are
+// signed with Secret when it is set.
+type WebhookCreateParams struct {
+	URL    string   `json:"url"`
+	Events []string `json:"events"`
+	Secret string   `json:"secret,omitempty"`
+}
+
+// WebhookTestResult is returned by Webhooks.Test.
+type WebhookTestResult struct {
+	Success bool   `json:"success"`
+	Message string `json:"message"`
+}
+
+func (s *WebhooksService) Create(ctx context.Context, params WebhookCreateParams) (*Webhook, error) {
+	var webhook Webhook
+	if err := s.c.doRaw(ctx, "POST", "/api/v1/webhooks", params, nil, &webhook); err != nil {
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+func (s *WebhooksService) List(ctx context.Context) ([]Webhook, error) {
+	var resp struct {
+		Webhooks json.RawMessage `json:"webhooks"`
+	}
+	if err := s.c.raw.RawRequestContext(ctx, "GET", "/api/v1/webhooks", nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	s.c.record(resp.Webhooks)
+	var webhooks []Webhook
+	if len(resp.Webhooks) > 0 {
+		if err := json.Unmarshal(resp.Webhooks, &webhooks); err != nil {
+			return nil, fmt.Errorf("failed to parse response: %w", err)
+		}
+	}
+	return webhooks, nil
+}
+
+func (s *WebhooksService) Delete(ctx context.Context, id string) error {
+	return s.c.doRaw(ctx, "DELETE", "/api/v1/webhooks/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// Test asks the hub to send a signed test ping to the webhook.
+func (s *WebhooksService) Test(ctx context.Context, id string) (*WebhookTestResult, error) {
+	var result WebhookTestResult
+	if err := s.c.doRaw(ctx, "POST", "/api/v1/webhooks/"+url.PathEscape(id)+"/test", nil, nil, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
@@ -0,0 +1,357 @@
+package output
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Output formats.
+const (
+	FormatTable  = "table"
+	FormatWide   = "wide"
+	FormatJSON   = "json"
+	FormatYAML   = "yaml"
+	FormatCSV    = "csv"
+	FormatTSV    = "tsv"
+	FormatNDJSON = "ndjson"
+)
+
+// Formats lists the output formats in the order shown in help.
+var Formats = []string{FormatTable, FormatJSON, FormatYAML, FormatCSV, FormatTSV, FormatNDJSON, FormatWide}
+
+// Formatter writes a command's result in one output format.
+type Formatter interface {
+	Format(w io.Writer, v interface{}) error
+}
+
+var formatters = map[string]Formatter{
+	FormatTable:  tableFormatter{},
+	FormatWide:   tableFormatter{wide: true},
+	FormatJSON:   jsonFormatter{},
+	FormatYAML:   yamlFormatter{},
+	FormatCSV:    delimitedFormatter{comma: ','},
+	FormatTSV:    delimitedFormatter{comma: '\t'},
+	FormatNDJSON: ndjsonFormatter{},
+}
+
+// ValidFormat reports whether name is a known output format.
+func ValidFormat(name string) bool {
+	_, ok := formatters[name]
+	return ok
+}
+
+// parseTemplate parses a --template. Templates run over the result's JSON
+// form, which for hub responses is the JSON the hub sent, so fields are named
+// as in --output json: {{.id}}, not {{.ID}}. The json function writes a value
+// as compact JSON.
+func parseTemplate(text string) (*template.Template, error) {
+	t, err := template.New("template").Funcs(template.FuncMap{
+		"json": func(v interface{}) (string, error) {
+			data, err := json.Marshal(v)
+			return string(data), err
+		},
+	}).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template: %w", err)
+	}
+	return t, nil
+}
+
+type templateFormatter struct {
+	tmpl *template.Template
+}
+
+func (f templateFormatter) Format(w io.Writer, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	var raw interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&raw); err != nil {
+		return err
+	}
+	return f.tmpl.Execute(w, raw)
+}
+
+type jsonFormatter struct{}
+
+func (jsonFormatter) Format(w io.Writer, v interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// ndjsonFormatter writes each element of a list as one compact JSON line,
+// and anything else as a single line.
+type ndjsonFormatter struct{}
+
+func (ndjsonFormatter) Format(w io.Writer, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	var items []json.RawMessage
+	if json.Unmarshal(data, &items) != nil {
+		items = []json.RawMessage{data}
+	}
+	for _, item := range items {
+		var buf bytes.Buffer
+		if err := json.Compact(&buf, item); err != nil {
+			return err
+		}
+		buf.WriteByte('\n')
+		if _, err := w.Write(buf.Bytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type yamlFormatter struct{}
+
+func (yamlFormatter) Format(w io.Writer, v interface{}) error {
+	data, err := decodeOrdered(v)
+	if err != nil {
+		return err
+	}
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(yamlNode(data)); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+func yamlNode(v interface{}) *yaml.Node {
+	switch v := v.(type) {
+	case *object:
+		n := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		for _, k := range v.keys {
+			n.Content = append(n.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: k}, yamlNode(v.values[k]))
+		}
+		return n
+	case []interface{}:
+		n := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		for _, item := range v {
+			n.Content = append(n.Content, yamlNode(item))
+		}
+		return n
+	case string:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: v}
+	case json.Number:
+		tag := "!!int"
+		if strings.ContainsAny(v.String(), ".eE") {
+			tag = "!!float"
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: v.String()}
+	case bool:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: fmt.Sprint(v)}
+	default:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}
+	}
+}
+
+// delimitedFormatter writes a list of objects as CSV or TSV with a header
+// row of field names. Nested values are written as compact JSON. TSV has no
+// quoting, so tabs and newlines in values become spaces.
+type delimitedFormatter struct {
+	comma rune
+}
+
+func (f delimitedFormatter) Format(w io.Writer, v interface{}) error {
+	data, err := decodeOrdered(v)
+	if err != nil {
+		return err
+	}
+	headers, rows := records(data)
+
+	if f.comma == '\t' {
+		for _, row := range append([][]string{headers}, rows...) {
+			for i := range row {
+				row[i] = flatten.Replace(row[i])
+			}
+			if _, err := fmt.Fprintln(w, strings.Join(row, "\t")); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	cw := csv.NewWriter(w)
+	cw.Comma = f.comma
+	cw.Write(headers)
+	cw.WriteAll(rows)
+	return cw.Error()
+}
+
+// records flattens a list of objects into rows, with columns for every field
+// in the order first seen. A single object is one row; scalars are one
+// "value" column.
+func records(data interface{}) ([]string, [][]string) {
+	var items []interface{}
+	switch d := data.(type) {
+	case []interface{}:
+		items = d
+	case nil:
+	default:
+		items = []interface{}{d}
+	}
+
+	var headers []string
+	seen := map[string]bool{}
+	for _, item := range items {
+		if obj, ok := item.(*object); ok {
+			for _, k := range obj.keys {
+				if !seen[k] {
+					seen[k] = true
+					headers = append(headers, k)
+				}
+			}
+		}
+	}
+
+	if len(headers) == 0 {
+		headers = []string{"value"}
+		rows := make([][]string, len(items))
+		for i, item := range items {
+			rows[i] = []string{cell(item)}
+		}
+		return headers, rows
+	}
+
+	rows := make([][]string, len(items))
+	for i, item := range items {
+		row := make([]string, len(headers))
+		if obj, ok := item.(*object); ok {
+			for j, h := range headers {
+				row[j] = cell(obj.values[h])
+			}
+		}
+		rows[i] = row
+	}
+	return headers, rows
+}
+
+// cell renders a decoded value as a single field.
+func cell(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		return fmt.Sprint(v)
+	default:
+		data, _ := json.Marshal(encodable(v))
+		return string(data)
+	}
+}
+
+// object is a JSON object that keeps its key order.
+type object struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+// decodeOrdered converts v to its JSON form, keeping object key order:
+// *object, []interface{}, string, json.Number, bool or nil.
+func decodeOrdered(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return decodeValue(dec)
+}
+
+func decodeValue(dec *json.Decoder) (interface{}, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch tok {
+	case json.Delim('{'):
+		obj := &object{values: map[string]interface{}{}}
+		for dec.More() {
+			keyTok, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			key := keyTok.(string)
+			val, err := decodeValue(dec)
+			if err != nil {
+				return nil, err
+			}
+			if _, dup := obj.values[key]; !dup {
+				obj.keys = append(obj.keys, key)
+			}
+			obj.values[key] = val
+		}
+		_, err := dec.Token()
+		return obj, err
+	case json.Delim('['):
+		list := []interface{}{}
+		for dec.More() {
+			val, err := decodeValue(dec)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, val)
+		}
+		_, err := dec.Token()
+		return list, err
+	default:
+		return tok, nil
+	}
+}
+
+// encodable turns decoded values back into something encoding/json writes
+// in the original key order.
+func encodable(v interface{}) interface{} {
+	switch v := v.(type) {
+	case *object:
+		return orderedJSON{v}
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			out[i] = encodable(item)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+type orderedJSON struct{ *object }
+
+func (o orderedJSON) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, k := range o.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, _ := json.Marshal(k)
+		val, err := json.Marshal(encodable(o.values[k]))
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(val)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
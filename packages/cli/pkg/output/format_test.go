@@ -0,0 +1,142 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+type testTask struct {
+	ID     string            `json:"id"`
+	Title  string            `json:"title"`
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+var testTasks = []testTask{
+	{ID: "t1", Title: "Write docs"},
+	{ID: "t2", Title: "Ship, then\trest", Labels: map[string]string{"team": "cli"}},
+}
+
+func TestFormatters(t *testing.T) {
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{FormatCSV, "id,title,labels\nt1,Write docs,\nt2,\"Ship, then\trest\",\"{\"\"team\"\":\"\"cli\"\"}\"\n"},
+		{FormatTSV, "id\ttitle\tlabels\nt1\tWrite docs\t\nt2\tShip, then rest\t{\"team\":\"cli\"}\n"},
+		{FormatNDJSON, "{\"id\":\"t1\",\"title\":\"Write docs\"}\n{\"id\":\"t2\",\"title\":\"Ship, then\\trest\",\"labels\":{\"team\":\"cli\"}}\n"},
+		{FormatYAML, "- id: t1\n  title: Write docs\n- id: t2\n  title: \"Ship, then\\trest\"\n  labels:\n    team: cli\n"},
+		{FormatTable, "ID  TITLE            LABELS\nt1  Write docs\nt2  Ship, then rest  {\"team\":\"cli\"}\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := formatters[tt.format].Format(&buf, testTasks); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("expected:\n%s\ngot:\n%s", tt.want, buf.String())
+			}
+		})
+	}
+}
+
+func TestFormatters_Object(t *testing.T) {
+	var buf bytes.Buffer
+	formatters[FormatTable].Format(&buf, testTasks[0])
+	if got := buf.String(); got != "FIELD  VALUE\nid     t1\ntitle  Write docs\n" {
+		t.Errorf("unexpected table:\n%s", got)
+	}
+
+	buf.Reset()
+	formatters[FormatNDJSON].Format(&buf, map[string]int{"count": 3})
+	if got := buf.String(); got != "{\"count\":3}\n" {
+		t.Errorf("unexpected ndjson: %s", got)
+	}
+}
+
+func TestTableFormatter_Wide(t *testing.T) {
+	table := &Table{
+		Headers: []string{"ID", "TITLE", "CREATED"},
+		Rows:    [][]string{{"t1", "Write docs", "2024-01-01"}},
+		Wide:    1,
+	}
+
+	var buf bytes.Buffer
+	formatters[FormatTable].Format(&buf, table)
+	if strings.Contains(buf.String(), "CREATED") {
+		t.Errorf("expected wide column hidden in table format, got:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	formatters[FormatWide].Format(&buf, table)
+	if !strings.Contains(buf.String(), "CREATED") || !strings.Contains(buf.String(), "2024-01-01") {
+		t.Errorf("expected wide column in wide format, got:\n%s", buf.String())
+	}
+}
+
+func TestStructured(t *testing.T) {
+	defer func() { Format = FormatTable }()
+	for _, f := range Formats {
+		Format = f
+		want := f != FormatTable && f != FormatWide
+		if Structured() != want {
+			t.Errorf("expected Structured()=%v for %s", want, f)
+		}
+	}
+	if ValidFormat("xml") {
+		t.Error("expected xml to be invalid")
+	}
+}
+
+func TestTemplateFormatter(t *testing.T) {
+	tmpl, err := parseTemplate(`{{range .}}{{.id}} {{json .labels}}{{"\n"}}{{end}}`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := (templateFormatter{tmpl}).Format(&buf, testTasks); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "t1 null\nt2 {\"team\":\"cli\"}\n"; buf.String() != want {
+		t.Errorf("expected %q, got %q", want, buf.String())
+	}
+
+	if _, err := parseTemplate("{{.id"); err == nil {
+		t.Error("expected error for an unterminated template")
+	}
+}
+
+func TestTemplateFormatter_RawJSON(t *testing.T) {
+	tmpl, err := parseTemplate(`{{.id}} {{.created_at}} {{.deleted_at}} {{.size}}`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var buf bytes.Buffer
+	raw := json.RawMessage(`{"id":"t1","created_at":"2024-01-02 15:04:05","deleted_at":null,"size":12345678901234567890}`)
+	if err := (templateFormatter{tmpl}).Format(&buf, raw); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "t1 2024-01-02 15:04:05 <no value> 12345678901234567890"; buf.String() != want {
+		t.Errorf("expected %q, got %q", want, buf.String())
+	}
+}
+
+func TestCheck(t *testing.T) {
+	defer func() { Format, Template, Query = FormatTable, "", "" }()
+
+	Template, Query = "{{.id}}", ".id"
+	if Check() == nil {
+		t.Error("expected error using --template with --query")
+	}
+	Template = ""
+	if Check() != nil || !Structured() {
+		t.Error("expected a valid query to make output structured")
+	}
+	Query = ".["
+	if Check() == nil {
+		t.Error("expected error for an invalid query")
+	}
+}
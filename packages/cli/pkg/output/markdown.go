@@ -0,0 +1,193 @@
+package output
+
+import (
+	"regexp"
+	"strings"
+)
+
+// defaultMarkdownWidth is the wrap width when the terminal's is unknown.
+const defaultMarkdownWidth = 80
+
+// Markdown renders Markdown for the terminal: headings, lists, quotes, code
+// blocks, emphasis and links, wrapped to the terminal width. When stdout is
+// not a terminal the text is returned unchanged.
+func Markdown(src string) string {
+	if !Terminal() {
+		return src
+	}
+	width := TerminalWidth()
+	if width <= 0 {
+		width = defaultMarkdownWidth
+	}
+	return renderMarkdown(src, width)
+}
+
+var (
+	mdFence    = regexp.MustCompile("^\\s*(```|~~~)")
+	mdHeading  = regexp.MustCompile(`^\s{0,3}(#{1,6})\s+(.*?)\s*#*\s*$`)
+	mdRule     = regexp.MustCompile(`^\s{0,3}([-*_])(\s*([-*_])){2,}\s*$`)
+	mdQuote    = regexp.MustCompile(`^\s{0,3}>\s?(.*)$`)
+	mdListItem = regexp.MustCompile(`^(\s*)([-*+]|\d+[.)])\s+(.*)$`)
+
+	mdCode   = regexp.MustCompile("`([^`]+)`")
+	mdImage  = regexp.MustCompile(`!\[([^\]]*)\]\(([^)\s]+)\)`)
+	mdLink   = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+	mdAuto   = regexp.MustCompile(`<(https?://[^>\s]+)>`)
+	mdBold   = regexp.MustCompile(`\*\*([^*]+)\*\*|__([^_]+)__`)
+	mdItalic = regexp.MustCompile(`\*([^*\s][^*]*)\*|(?:^|\b)_([^_\s][^_]*)_(?:\b|$)`)
+)
+
+// mdBlock is a paragraph, list item or quote waiting to be wrapped.
+type mdBlock struct {
+	first, rest string
+	quote       bool
+	lines       []string
+}
+
+func renderMarkdown(src string, width int) string {
+	var out []string
+	var block *mdBlock
+	inCode := false
+
+	emit := func(lines ...string) {
+		for _, l := range lines {
+			if l == "" && (len(out) == 0 || out[len(out)-1] == "") {
+				continue
+			}
+			out = append(out, l)
+		}
+	}
+	flush := func() {
+		if block != nil {
+			emit(wrap(inlineMarkdown(strings.Join(block.lines, " ")), width, block.first, block.rest)...)
+			block = nil
+		}
+	}
+
+	for _, line := range strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n") {
+		line = strings.ReplaceAll(line, "\t", "    ")
+		if mdFence.MatchString(line) {
+			flush()
+			inCode = !inCode
+			continue
+		}
+		if inCode {
+			out = append(out, "    "+paint(line, styleCyan))
+			continue
+		}
+
+		if strings.TrimSpace(line) == "" {
+			flush()
+			emit("")
+			continue
+		}
+		if m := mdHeading.FindStringSubmatch(line); m != nil {
+			flush()
+			styles := []string{styleBold}
+			if len(m[1]) == 1 {
+				styles = append(styles, styleUnderline)
+			}
+			emit("")
+			for _, l := range wrap(stripANSI(inlineMarkdown(m[2])), width, "", "") {
+				emit(paint(l, styles...))
+			}
+			emit("")
+			continue
+		}
+		if mdRule.MatchString(line) {
+			flush()
+			emit(paint(strings.Repeat("─", width), styleDim))
+			continue
+		}
+		if m := mdQuote.FindStringSubmatch(line); m != nil {
+			if block == nil || !block.quote {
+				flush()
+				bar := paint("│", styleDim) + " "
+				block = &mdBlock{first: bar, rest: bar, quote: true}
+			}
+			block.lines = append(block.lines, m[1])
+			continue
+		}
+		if m := mdListItem.FindStringSubmatch(line); m != nil {
+			flush()
+			indent := strings.Repeat("  ", len(m[1])/2+1)
+			marker := m[2]
+			if strings.ContainsAny(marker, "-*+") {
+				marker = "•"
+			}
+			block = &mdBlock{
+				first: indent + paint(marker, styleDim) + " ",
+				rest:  indent + strings.Repeat(" ", Width(marker)+1),
+				lines: []string{m[3]},
+			}
+			continue
+		}
+
+		if block == nil {
+			block = &mdBlock{}
+		}
+		block.lines = append(block.lines, strings.TrimSpace(line))
+	}
+	flush()
+
+	for len(out) > 0 && out[len(out)-1] == "" {
+		out = out[:len(out)-1]
+	}
+	return strings.Join(out, "\n")
+}
+
+// inlineMarkdown renders code spans, links, bold and italic text.
+func inlineMarkdown(s string) string {
+	// Code spans are left alone, so split them out first.
+	var b strings.Builder
+	last := 0
+	for _, m := range mdCode.FindAllStringSubmatchIndex(s, -1) {
+		b.WriteString(inlineText(s[last:m[0]]))
+		b.WriteString(paint(s[m[2]:m[3]], styleCyan))
+		last = m[1]
+	}
+	b.WriteString(inlineText(s[last:]))
+	return b.String()
+}
+
+func inlineText(s string) string {
+	s = mdImage.ReplaceAllString(s, "[image: $1] ($2)")
+	s = mdLink.ReplaceAllStringFunc(s, func(m string) string {
+		sub := mdLink.FindStringSubmatch(m)
+		if sub[1] == sub[2] {
+			return paint(sub[2], styleUnderline, styleBlue)
+		}
+		return paint(sub[1], styleUnderline, styleBlue) + " (" + sub[2] + ")"
+	})
+	s = mdAuto.ReplaceAllStringFunc(s, func(m string) string {
+		return paint(mdAuto.FindStringSubmatch(m)[1], styleUnderline, styleBlue)
+	})
+	s = mdBold.ReplaceAllStringFunc(s, func(m string) string {
+		sub := mdBold.FindStringSubmatch(m)
+		return paint(sub[1]+sub[2], styleBold)
+	})
+	s = mdItalic.ReplaceAllStringFunc(s, func(m string) string {
+		sub := mdItalic.FindStringSubmatch(m)
+		return paint(sub[1]+sub[2], styleItalic)
+	})
+	return s
+}
+
+// wrap breaks text into lines of at most width cells, starting the first
+// with first and the others with rest. Words longer than a line are not
+// split.
+func wrap(text string, width int, first, rest string) []string {
+	var lines []string
+	line, prefix := "", first
+	for _, word := range strings.Fields(text) {
+		if line != "" && Width(prefix)+Width(line)+1+Width(word) > width {
+			lines = append(lines, prefix+line)
+			line, prefix = "", rest
+		}
+		if line != "" {
+			line += " "
+		}
+		line += word
+	}
+	return append(lines, prefix+line)
+}
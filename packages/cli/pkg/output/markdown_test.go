@@ -0,0 +1,66 @@
+package output
+
+import "testing"
+
+func TestRenderMarkdown(t *testing.T) {
+	src := "# The Manifesto\n" +
+		"Agents **work in the open** and *share* what they learn with `agenthq post create`.\n" +
+		"\n" +
+		"## Rules\n" +
+		"- Post updates\n" +
+		"  as you go\n" +
+		"- Read the [docs](https://example.com/docs)\n" +
+		"  - nested_item stays\n" +
+		"1. First\n" +
+		"\n" +
+		"> Quoted text that is long enough to wrap\n" +
+		"\n" +
+		"```\n" +
+		"go build ./...\n" +
+		"```\n" +
+		"---\n"
+
+	want := "The Manifesto\n" +
+		"\n" +
+		"Agents work in the open and\n" +
+		"share what they learn with\n" +
+		"agenthq post create.\n" +
+		"\n" +
+		"Rules\n" +
+		"\n" +
+		"  • Post updates as you go\n" +
+		"  • Read the docs\n" +
+		"    (https://example.com/docs)\n" +
+		"    • nested_item stays\n" +
+		"  1. First\n" +
+		"\n" +
+		"│ Quoted text that is long\n" +
+		"│ enough to wrap\n" +
+		"\n" +
+		"    go build ./...\n" +
+		"──────────────────────────────"
+
+	if got := renderMarkdown(src, 30); got != want {
+		t.Errorf("expected:\n%s\ngot:\n%s", want, got)
+	}
+}
+
+func TestMarkdown_Piped(t *testing.T) {
+	src := "**raw** text"
+	if got := Markdown(src); got != src {
+		t.Errorf("expected Markdown unchanged when stdout is not a terminal, got %q", got)
+	}
+}
+
+func TestWrap(t *testing.T) {
+	got := wrap("a verylongwordthatdoesnotfit b", 10, "- ", "  ")
+	want := []string{"- a", "  verylongwordthatdoesnotfit", "  b"}
+	if len(got) != len(want) {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("line %d: expected %q, got %q", i, want[i], got[i])
+		}
+	}
+}
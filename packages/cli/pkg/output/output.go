@@ -4,10 +4,67 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
-	"text/tabwriter"
+	"strings"
 )
 
-var JSONMode bool
+// Format is the output format, one of Formats. It is bound to the global
+// --output flag.
+var Format = FormatTable
+
+// Template and Query, bound to --template and --query, replace the output
+// format with a Go template or a query over the result's JSON form.
+var (
+	Template string
+	Query    string
+)
+
+// Structured reports whether results are printed as data (JSON, YAML, CSV,
+// a template or query, ...) rather than human-readable text. Commands with
+// their own text views print those only when this is false.
+func Structured() bool {
+	return Template != "" || Query != "" || (Format != FormatTable && Format != FormatWide)
+}
+
+// Check reports an invalid Format, Template or Query.
+func Check() error {
+	_, err := formatter()
+	return err
+}
+
+func formatter() (Formatter, error) {
+	switch {
+	case Template != "" && Query != "":
+		return nil, fmt.Errorf("--template and --query cannot be used together")
+	case Template != "":
+		t, err := parseTemplate(Template)
+		if err != nil {
+			return nil, err
+		}
+		return templateFormatter{t}, nil
+	case Query != "":
+		q, err := parseQuery(Query)
+		if err != nil {
+			return nil, err
+		}
+		return queryFormatter{q}, nil
+	}
+	f, ok := formatters[Format]
+	if !ok {
+		return nil, fmt.Errorf("invalid --output %q: must be one of %s", Format, strings.Join(Formats, ", "))
+	}
+	return f, nil
+}
+
+// Print writes a command's result to stdout in the current format. In table
+// formats a *Table is rendered as is and other values are laid out as a
+// table of their fields.
+func Print(v interface{}) error {
+	f, err := formatter()
+	if err != nil {
+		return err
+	}
+	return f.Format(os.Stdout, v)
+}
 
 func PrintJSON(v interface{}) {
 	enc := json.NewEncoder(os.Stdout)
@@ -16,40 +73,45 @@ func PrintJSON(v interface{}) {
 }
 
 func PrintSuccess(msg string) {
-	if JSONMode {
-		PrintJSON(map[string]interface{}{"status": "success", "message": msg})
+	if Structured() {
+		Print(map[string]interface{}{"status": "success", "message": msg})
 		return
 	}
-	fmt.Printf("✓ %s\n", msg)
+	fmt.Printf("%s %s\n", paint("✓", styleGreen), msg)
+}
+
+// ErrorInfo is the JSON form of an error. Code is the hub's error code when
+// the hub reported one; Details carries its field-level validation errors.
+type ErrorInfo struct {
+	Status     string      `json:"status"`
+	Code       string      `json:"code,omitempty"`
+	HTTPStatus int         `json:"http_status,omitempty"`
+	Message    string      `json:"message"`
+	Details    interface{} `json:"details,omitempty"`
 }
 
 func PrintError(msg string) {
-	if JSONMode {
-		PrintJSON(map[string]interface{}{"status": "error", "message": msg})
-		return
-	}
-	fmt.Fprintf(os.Stderr, "✗ %s\n", msg)
+	PrintErrorInfo(ErrorInfo{Message: msg})
 }
 
-func PrintTable(headers []string, rows [][]string) {
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	// Print headers
-	for i, h := range headers {
-		if i > 0 {
-			fmt.Fprint(w, "\t")
+// PrintErrorInfo writes info to stderr, as JSON in structured formats.
+func PrintErrorInfo(info ErrorInfo) {
+	if Structured() {
+		info.Status = "error"
+		enc := json.NewEncoder(os.Stderr)
+		if Format != FormatNDJSON {
+			enc.SetIndent("", "  ")
 		}
-		fmt.Fprint(w, h)
+		enc.Encode(info)
+		return
 	}
-	fmt.Fprintln(w)
-	// Print rows
-	for _, row := range rows {
-		for i, col := range row {
-			if i > 0 {
-				fmt.Fprint(w, "\t")
-			}
-			fmt.Fprint(w, col)
-		}
-		fmt.Fprintln(w)
+	mark := "✗"
+	if colorTo(os.Stderr) {
+		mark = ansi(mark, styleRed)
 	}
-	w.Flush()
+	fmt.Fprintf(os.Stderr, "%s %s\n", mark, info.Message)
+}
+
+func PrintTable(headers []string, rows [][]string) error {
+	return Print(&Table{Headers: headers, Rows: rows})
 }
@@ -59,8 +59,8 @@ func TestPrintJSON(t *testing.T) {
 }
 
 func TestPrintSuccess_TextMode(t *testing.T) {
-	JSONMode = false
-	defer func() { JSONMode = false }()
+	Format = FormatTable
+	defer func() { Format = FormatTable }()
 
 	got := captureStdout(func() {
 		PrintSuccess("operation completed")
@@ -75,8 +75,8 @@ func TestPrintSuccess_TextMode(t *testing.T) {
 }
 
 func TestPrintSuccess_JSONMode(t *testing.T) {
-	JSONMode = true
-	defer func() { JSONMode = false }()
+	Format = FormatJSON
+	defer func() { Format = FormatTable }()
 
 	got := captureStdout(func() {
 		PrintSuccess("operation completed")
@@ -96,8 +96,8 @@ func TestPrintSuccess_JSONMode(t *testing.T) {
 }
 
 func TestPrintError_TextMode(t *testing.T) {
-	JSONMode = false
-	defer func() { JSONMode = false }()
+	Format = FormatTable
+	defer func() { Format = FormatTable }()
 
 	got := captureStderr(func() {
 		PrintError("something went wrong")
@@ -112,13 +112,18 @@ func TestPrintError_TextMode(t *testing.T) {
 }
 
 func TestPrintError_JSONMode(t *testing.T) {
-	JSONMode = true
-	defer func() { JSONMode = false }()
+	Format = FormatJSON
+	defer func() { Format = FormatTable }()
 
-	// In JSON mode, PrintError calls PrintJSON which writes to os.Stdout
-	got := captureStdout(func() {
-		PrintError("something went wrong")
+	var stdout string
+	got := captureStderr(func() {
+		stdout = captureStdout(func() {
+			PrintError("something went wrong")
+		})
 	})
+	if stdout != "" {
+		t.Errorf("expected nothing on stdout, got: %s", stdout)
+	}
 
 	var parsed map[string]interface{}
 	if err := json.Unmarshal([]byte(got), &parsed); err != nil {
@@ -133,6 +138,23 @@ func TestPrintError_JSONMode(t *testing.T) {
 	}
 }
 
+func TestPrintErrorInfo_JSONMode(t *testing.T) {
+	Format = FormatJSON
+	defer func() { Format = FormatTable }()
+
+	got := captureStderr(func() {
+		PrintErrorInfo(ErrorInfo{Code: "NOT_FOUND", HTTPStatus: 404, Message: "Task not found"})
+	})
+
+	var parsed map[string]interface{}
+	if err := json.Unmarshal([]byte(got), &parsed); err != nil {
+		t.Fatalf("expected valid JSON, got error: %v\nOutput: %s", err, got)
+	}
+	if parsed["status"] != "error" || parsed["code"] != "NOT_FOUND" || parsed["http_status"] != float64(404) {
+		t.Errorf("unexpected error JSON: %v", parsed)
+	}
+}
+
 func TestPrintTable(t *testing.T) {
 	headers := []string{"ID", "NAME", "STATUS"}
 	rows := [][]string{
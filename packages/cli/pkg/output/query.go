@@ -0,0 +1,354 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+)
+
+// query is a parsed --query expression, a small subset of jq:
+//
+//	.               the whole result
+//	.id  .author.name  .["created at"]
+//	.[0]  .[-1]     array elements, counting from the end when negative
+//	.[]             every element of an array or value of an object
+//	a | b           run b on each result of a
+//	a, b            the results of a, then of b
+//	length  keys    the length of a string, array or object; an object's keys
+type query struct {
+	pipeline [][][]step
+}
+
+type stepKind int
+
+const (
+	stepField stepKind = iota
+	stepIndex
+	stepIterate
+	stepLength
+	stepKeys
+)
+
+type step struct {
+	kind  stepKind
+	field string
+	index int
+}
+
+// parseQuery parses a --query expression.
+func parseQuery(expr string) (*query, error) {
+	p := &queryParser{src: expr}
+	q := &query{}
+	for {
+		alts, err := p.alternatives()
+		if err != nil {
+			return nil, err
+		}
+		q.pipeline = append(q.pipeline, alts)
+		p.space()
+		if p.done() {
+			return q, nil
+		}
+		if !p.consume('|') {
+			return nil, p.errorf("unexpected %q", p.src[p.pos])
+		}
+	}
+}
+
+// run evaluates the query against a value decoded by decodeOrdered.
+func (q *query) run(v interface{}) ([]interface{}, error) {
+	values := []interface{}{v}
+	for _, alts := range q.pipeline {
+		var next []interface{}
+		for _, in := range values {
+			for _, path := range alts {
+				out, err := runPath(path, in)
+				if err != nil {
+					return nil, err
+				}
+				next = append(next, out...)
+			}
+		}
+		values = next
+	}
+	return values, nil
+}
+
+func runPath(path []step, v interface{}) ([]interface{}, error) {
+	values := []interface{}{v}
+	for _, s := range path {
+		var next []interface{}
+		for _, in := range values {
+			out, err := s.apply(in)
+			if err != nil {
+				return nil, err
+			}
+			next = append(next, out...)
+		}
+		values = next
+	}
+	return values, nil
+}
+
+func (s step) apply(v interface{}) ([]interface{}, error) {
+	switch s.kind {
+	case stepField:
+		switch v := v.(type) {
+		case nil:
+			return []interface{}{nil}, nil
+		case *object:
+			return []interface{}{v.values[s.field]}, nil
+		}
+		return nil, fmt.Errorf("cannot index %s with %q", typeName(v), s.field)
+	case stepIndex:
+		switch v := v.(type) {
+		case nil:
+			return []interface{}{nil}, nil
+		case []interface{}:
+			i := s.index
+			if i < 0 {
+				i += len(v)
+			}
+			if i < 0 || i >= len(v) {
+				return []interface{}{nil}, nil
+			}
+			return []interface{}{v[i]}, nil
+		}
+		return nil, fmt.Errorf("cannot index %s with %d", typeName(v), s.index)
+	case stepIterate:
+		switch v := v.(type) {
+		case []interface{}:
+			return v, nil
+		case *object:
+			out := make([]interface{}, len(v.keys))
+			for i, k := range v.keys {
+				out[i] = v.values[k]
+			}
+			return out, nil
+		}
+		return nil, fmt.Errorf("cannot iterate over %s", typeName(v))
+	case stepLength:
+		switch v := v.(type) {
+		case nil:
+			return []interface{}{json.Number("0")}, nil
+		case string:
+			return []interface{}{json.Number(strconv.Itoa(len([]rune(v))))}, nil
+		case []interface{}:
+			return []interface{}{json.Number(strconv.Itoa(len(v)))}, nil
+		case *object:
+			return []interface{}{json.Number(strconv.Itoa(len(v.keys)))}, nil
+		}
+		return nil, fmt.Errorf("%s has no length", typeName(v))
+	case stepKeys:
+		switch v := v.(type) {
+		case []interface{}:
+			out := make([]interface{}, len(v))
+			for i := range v {
+				out[i] = json.Number(strconv.Itoa(i))
+			}
+			return []interface{}{out}, nil
+		case *object:
+			keys := append([]string(nil), v.keys...)
+			sort.Strings(keys)
+			out := make([]interface{}, len(keys))
+			for i, k := range keys {
+				out[i] = k
+			}
+			return []interface{}{out}, nil
+		}
+		return nil, fmt.Errorf("%s has no keys", typeName(v))
+	}
+	return nil, fmt.Errorf("unknown query step")
+}
+
+func typeName(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case json.Number:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+type queryParser struct {
+	src string
+	pos int
+}
+
+func (p *queryParser) done() bool { return p.pos >= len(p.src) }
+
+func (p *queryParser) peek() byte {
+	if p.done() {
+		return 0
+	}
+	return p.src[p.pos]
+}
+
+func (p *queryParser) space() {
+	for !p.done() && (p.peek() == ' ' || p.peek() == '\t' || p.peek() == '\n') {
+		p.pos++
+	}
+}
+
+func (p *queryParser) consume(c byte) bool {
+	if p.peek() == c {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *queryParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("invalid query at column %d: %s", p.pos+1, fmt.Sprintf(format, args...))
+}
+
+func (p *queryParser) alternatives() ([][]step, error) {
+	var alts [][]step
+	for {
+		path, err := p.term()
+		if err != nil {
+			return nil, err
+		}
+		alts = append(alts, path)
+		p.space()
+		if !p.consume(',') {
+			return alts, nil
+		}
+	}
+}
+
+func (p *queryParser) term() ([]step, error) {
+	p.space()
+	if p.done() {
+		return nil, p.errorf("expected an expression")
+	}
+	if name := p.ident(); name != "" {
+		switch name {
+		case "length":
+			return []step{{kind: stepLength}}, nil
+		case "keys":
+			return []step{{kind: stepKeys}}, nil
+		}
+		return nil, p.errorf("unknown function %q", name)
+	}
+	if !p.consume('.') {
+		return nil, p.errorf("expected '.', got %q", p.peek())
+	}
+
+	var path []step
+	if name := p.ident(); name != "" {
+		path = append(path, step{kind: stepField, field: name})
+	}
+	for {
+		switch {
+		case p.consume('.'):
+			if p.peek() == '[' {
+				continue
+			}
+			name := p.ident()
+			if name == "" {
+				return nil, p.errorf("expected a field name after '.'")
+			}
+			path = append(path, step{kind: stepField, field: name})
+		case p.consume('['):
+			s, err := p.bracket()
+			if err != nil {
+				return nil, err
+			}
+			path = append(path, s)
+		default:
+			return path, nil
+		}
+	}
+}
+
+// bracket parses the inside of [], [n] or ["field"] after the '['.
+func (p *queryParser) bracket() (step, error) {
+	p.space()
+	var s step
+	switch {
+	case p.peek() == ']':
+		s = step{kind: stepIterate}
+	case p.peek() == '"':
+		start := p.pos
+		for p.pos++; !p.done() && p.peek() != '"'; p.pos++ {
+			if p.peek() == '\\' {
+				p.pos++
+			}
+		}
+		if !p.consume('"') {
+			return s, p.errorf("unterminated string")
+		}
+		field, err := strconv.Unquote(p.src[start:p.pos])
+		if err != nil {
+			return s, p.errorf("invalid string %s", p.src[start:p.pos])
+		}
+		s = step{kind: stepField, field: field}
+	default:
+		start := p.pos
+		p.consume('-')
+		for !p.done() && p.peek() >= '0' && p.peek() <= '9' {
+			p.pos++
+		}
+		n, err := strconv.Atoi(p.src[start:p.pos])
+		if err != nil {
+			return s, p.errorf("expected an index, a quoted field or ]")
+		}
+		s = step{kind: stepIndex, index: n}
+	}
+	p.space()
+	if !p.consume(']') {
+		return s, p.errorf("expected ]")
+	}
+	return s, nil
+}
+
+func (p *queryParser) ident() string {
+	start := p.pos
+	for !p.done() {
+		c := p.peek()
+		if c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (p.pos > start && c >= '0' && c <= '9') {
+			p.pos++
+			continue
+		}
+		break
+	}
+	return p.src[start:p.pos]
+}
+
+// queryFormatter writes each query result on its own line: strings as they
+// are, anything else as compact JSON.
+type queryFormatter struct {
+	query *query
+}
+
+func (f queryFormatter) Format(w io.Writer, v interface{}) error {
+	data, err := decodeOrdered(v)
+	if err != nil {
+		return err
+	}
+	results, err := f.query.run(data)
+	if err != nil {
+		return err
+	}
+	for _, r := range results {
+		line := "null"
+		if r != nil {
+			line = cell(r)
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
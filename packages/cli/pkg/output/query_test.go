@@ -0,0 +1,84 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestQuery(t *testing.T) {
+	data := []map[string]interface{}{
+		{"id": "p1", "author": map[string]string{"name": "Ada"}, "tags": []string{"go", "cli"}, "score": 3},
+		{"id": "p2", "author": nil, "tags": []string{}, "score": 1.5},
+	}
+
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{".", `[{"author":{"name":"Ada"},"id":"p1","score":3,"tags":["go","cli"]},{"author":null,"id":"p2","score":1.5,"tags":[]}]` + "\n"},
+		{".[].id", "p1\np2\n"},
+		{".[0].author.name", "Ada\n"},
+		{".[1].author.name", "null\n"},
+		{".[-1].score", "1.5\n"},
+		{".[5]", "null\n"},
+		{`.[0]["tags"][1]`, "cli\n"},
+		{".[].tags | length", "2\n0\n"},
+		{".[0] | keys", `["author","id","score","tags"]` + "\n"},
+		{".[0].id, .[1].id", "p1\np2\n"},
+		{".[0].tags[]", "go\ncli\n"},
+		{" .[] | .id ", "p1\np2\n"},
+		{"length", "2\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			q, err := parseQuery(tt.query)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var buf bytes.Buffer
+			if err := (queryFormatter{q}).Format(&buf, data); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, buf.String())
+			}
+		})
+	}
+}
+
+func TestQuery_RawJSON(t *testing.T) {
+	raw := json.RawMessage(`{"id":"t1","created_at":"2024-01-02 15:04:05","deleted_at":null,"extra":{"b":1,"a":2}}`)
+	for query, want := range map[string]string{
+		".created_at": "2024-01-02 15:04:05\n",
+		".deleted_at": "null\n",
+		".extra":      `{"b":1,"a":2}` + "\n",
+	} {
+		q, err := parseQuery(query)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var buf bytes.Buffer
+		if err := (queryFormatter{q}).Format(&buf, raw); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if buf.String() != want {
+			t.Errorf("%s: expected %q, got %q", query, want, buf.String())
+		}
+	}
+}
+
+func TestQuery_Errors(t *testing.T) {
+	for _, expr := range []string{"", "id", ".[", ".[x]", ".a.", `.["a]`, ".a |", "count"} {
+		if _, err := parseQuery(expr); err == nil {
+			t.Errorf("expected parse error for %q", expr)
+		}
+	}
+
+	q, _ := parseQuery(".id")
+	var buf bytes.Buffer
+	if err := (queryFormatter{q}).Format(&buf, []string{"a"}); err == nil {
+		t.Error("expected error indexing an array with a field")
+	}
+}
@@ -0,0 +1,196 @@
+package output
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/term"
+)
+
+// NoColor, bound to the global --no-color flag, turns off colored output.
+// Setting the NO_COLOR environment variable does the same.
+var NoColor bool
+
+// now is replaced in tests.
+var now = time.Now
+
+// Terminal reports whether stdout is a terminal. Relative times and colors
+// are only used when it is, so piped output stays plain and stable.
+func Terminal() bool {
+	return term.IsTerminal(int(os.Stdout.Fd()))
+}
+
+// Color reports whether output is colored: stdout is a terminal, and
+// neither --no-color nor NO_COLOR is set.
+func Color() bool {
+	return colorTo(os.Stdout)
+}
+
+func colorTo(f *os.File) bool {
+	return !NoColor && os.Getenv("NO_COLOR") == "" && os.Getenv("TERM") != "dumb" && term.IsTerminal(int(f.Fd()))
+}
+
+// ANSI styles.
+const (
+	styleBold      = "1"
+	styleDim       = "2"
+	styleItalic    = "3"
+	styleUnderline = "4"
+	styleRed       = "31"
+	styleGreen     = "32"
+	styleYellow    = "33"
+	styleBlue      = "34"
+	styleCyan      = "36"
+)
+
+// paint wraps s in the given ANSI styles when output is colored.
+func paint(s string, styles ...string) string {
+	if !Color() {
+		return s
+	}
+	return ansi(s, styles...)
+}
+
+func ansi(s string, styles ...string) string {
+	if s == "" || len(styles) == 0 {
+		return s
+	}
+	return "\x1b[" + strings.Join(styles, ";") + "m" + s + "\x1b[0m"
+}
+
+// badges maps statuses, priorities and post types to their styles.
+var badges = map[string][]string{
+	// Task statuses.
+	"open":        {styleBlue},
+	"in_progress": {styleCyan},
+	"completed":   {styleGreen},
+	"cancelled":   {styleDim},
+
+	// Task priorities.
+	"low":    {styleDim},
+	"medium": {styleYellow},
+	"high":   {styleRed},
+	"urgent": {styleBold, styleRed},
+
+	// Agent presence.
+	"online":  {styleGreen},
+	"busy":    {styleYellow},
+	"offline": {styleDim},
+
+	// Post types.
+	"alert": {styleBold, styleRed},
+}
+
+// Badge colors a task status or priority, agent status or post type.
+// Other values are returned unchanged.
+func Badge(s string) string {
+	return paint(s, badges[strings.ToLower(s)]...)
+}
+
+// Bold emphasizes s.
+func Bold(s string) string {
+	return paint(s, styleBold)
+}
+
+// Dim de-emphasizes secondary text such as IDs.
+func Dim(s string) string {
+	return paint(s, styleDim)
+}
+
+// Time formats t with layout, or as a relative time like "3m ago" when
+// stdout is a terminal.
+func Time(t time.Time, layout string) string {
+	if t.IsZero() {
+		return ""
+	}
+	if Terminal() {
+		return RelativeTime(t)
+	}
+	return t.Format(layout)
+}
+
+// TimeString is Time for an RFC 3339 timestamp, which is returned as is
+// when stdout is not a terminal or it does not parse.
+func TimeString(s string) string {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil || !Terminal() {
+		return s
+	}
+	return RelativeTime(t)
+}
+
+// RelativeTime describes t relative to now: "just now", "3m ago", "in 2h".
+func RelativeTime(t time.Time) string {
+	d := now().Sub(t)
+	future := d < 0
+	if future {
+		d = -d
+	}
+	if d < 10*time.Second {
+		return "just now"
+	}
+
+	var n int
+	var unit string
+	switch {
+	case d < time.Minute:
+		n, unit = int(d/time.Second), "s"
+	case d < time.Hour:
+		n, unit = int(d/time.Minute), "m"
+	case d < 24*time.Hour:
+		n, unit = int(d/time.Hour), "h"
+	case d < 30*24*time.Hour:
+		n, unit = int(d/(24*time.Hour)), "d"
+	case d < 365*24*time.Hour:
+		n, unit = int(d/(30*24*time.Hour)), "mo"
+	default:
+		n, unit = int(d/(365*24*time.Hour)), "y"
+	}
+	if future {
+		return fmt.Sprintf("in %d%s", n, unit)
+	}
+	return fmt.Sprintf("%d%s ago", n, unit)
+}
+
+var relativeUnits = map[string]time.Duration{
+	"s":  time.Second,
+	"m":  time.Minute,
+	"h":  time.Hour,
+	"d":  24 * time.Hour,
+	"mo": 30 * 24 * time.Hour,
+	"y":  365 * 24 * time.Hour,
+}
+
+var relativeTime = regexp.MustCompile(`^(in )?(\d+)(s|m|h|d|mo|y)( ago)?$`)
+
+// parseRelative turns a RelativeTime back into an offset from now, so tables
+// sort relative times chronologically.
+func parseRelative(s string) (time.Duration, bool) {
+	if s == "just now" {
+		return 0, true
+	}
+	m := relativeTime.FindStringSubmatch(s)
+	if m == nil || (m[1] == "") == (m[4] == "") {
+		return 0, false
+	}
+	n, _ := strconv.Atoi(m[2])
+	d := time.Duration(n) * relativeUnits[m[3]]
+	if m[4] != "" {
+		d = -d
+	}
+	return d, true
+}
+
+var ansiEscape = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+// stripANSI removes color codes from s.
+func stripANSI(s string) string {
+	if !strings.Contains(s, "\x1b") {
+		return s
+	}
+	return ansiEscape.ReplaceAllString(s, "")
+}
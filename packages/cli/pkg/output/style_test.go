@@ -0,0 +1,74 @@
+package output
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRelativeTime(t *testing.T) {
+	base := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	now = func() time.Time { return base }
+	defer func() { now = time.Now }()
+
+	tests := []struct {
+		ago  time.Duration
+		want string
+	}{
+		{3 * time.Second, "just now"},
+		{45 * time.Second, "45s ago"},
+		{3 * time.Minute, "3m ago"},
+		{2*time.Hour + 59*time.Minute, "2h ago"},
+		{5 * 24 * time.Hour, "5d ago"},
+		{65 * 24 * time.Hour, "2mo ago"},
+		{800 * 24 * time.Hour, "2y ago"},
+		{-90 * time.Minute, "in 1h"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			got := RelativeTime(base.Add(-tt.ago))
+			if got != tt.want {
+				t.Errorf("RelativeTime(-%v) = %q, want %q", tt.ago, got, tt.want)
+			}
+			if _, ok := parseRelative(got); !ok {
+				t.Errorf("parseRelative(%q) failed", got)
+			}
+		})
+	}
+}
+
+func TestLessCell_RelativeTimes(t *testing.T) {
+	ordered := []string{"2y ago", "3d ago", "5m ago", "just now", "in 2h"}
+	for i := 0; i < len(ordered)-1; i++ {
+		if !lessCell(ordered[i], ordered[i+1]) || lessCell(ordered[i+1], ordered[i]) {
+			t.Errorf("expected %q to sort before %q", ordered[i], ordered[i+1])
+		}
+	}
+	if _, ok := parseRelative("3m"); ok {
+		t.Error("expected a bare duration not to parse as a relative time")
+	}
+}
+
+func TestColor(t *testing.T) {
+	NoColor = false
+	defer func() { NoColor = false }()
+
+	// Tests do not write to a terminal, so nothing is colored.
+	if Color() || Badge("urgent") != "urgent" {
+		t.Error("expected no color when stdout is not a terminal")
+	}
+	if got := TimeString("2024-06-01T12:00:00Z"); got != "2024-06-01T12:00:00Z" {
+		t.Errorf("expected timestamps unchanged when piped, got %q", got)
+	}
+
+	colored := ansi("urgent", styleBold, styleRed)
+	if colored != "\x1b[1;31murgent\x1b[0m" {
+		t.Errorf("unexpected escape sequence %q", colored)
+	}
+	if Width(colored) != 6 {
+		t.Errorf("expected colors to take no width, got %d", Width(colored))
+	}
+	if got := Truncate(ansi("in_progress", styleCyan), 8); got != "in_pr..." {
+		t.Errorf("expected truncation of the plain text, got %q", got)
+	}
+}
@@ -0,0 +1,308 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mattn/go-runewidth"
+	"golang.org/x/term"
+)
+
+// Table options, bound to the global --columns, --sort-by and --no-headers
+// flags.
+var (
+	// Columns selects and orders the columns shown, by header name.
+	Columns []string
+	// SortBy sorts rows by a column; a leading "-" sorts descending.
+	SortBy    string
+	NoHeaders bool
+)
+
+// Table is the human-readable view of a result.
+type Table struct {
+	Headers []string
+	Rows    [][]string
+	// Wide is the number of trailing columns shown only in the wide format.
+	Wide int
+	// Flexible names the columns that are truncated when the table is wider
+	// than the terminal. By default every column but the first is.
+	Flexible []string
+}
+
+// minFlexWidth is the narrowest a flexible column is truncated to, unless
+// its header is wider.
+const minFlexWidth = 10
+
+// flatten keeps tabs and newlines in a value from breaking its row.
+var flatten = strings.NewReplacer("\t", " ", "\r\n", " ", "\n", " ", "\r", " ")
+
+type tableFormatter struct {
+	wide bool
+}
+
+func (f tableFormatter) Format(w io.Writer, v interface{}) error {
+	t, ok := v.(*Table)
+	if !ok {
+		var err error
+		if t, err = tableOf(v); err != nil {
+			return err
+		}
+	}
+
+	cols, err := t.columns(f.wide)
+	if err != nil {
+		return err
+	}
+	rows, err := t.sorted()
+	if err != nil {
+		return err
+	}
+
+	headers := make([]string, len(cols))
+	widths := make([]int, len(cols))
+	for j, c := range cols {
+		headers[j] = t.Headers[c]
+		if !NoHeaders {
+			widths[j] = Width(headers[j])
+		}
+	}
+	lines := make([][]string, len(rows))
+	for i, row := range rows {
+		lines[i] = make([]string, len(cols))
+		for j, c := range cols {
+			if c < len(row) {
+				lines[i][j] = flatten.Replace(row[c])
+			}
+			if cw := Width(lines[i][j]); cw > widths[j] {
+				widths[j] = cw
+			}
+		}
+	}
+	if !f.wide {
+		t.fit(cols, widths, TerminalWidth())
+	}
+
+	if !NoHeaders {
+		lines = append([][]string{headers}, lines...)
+	}
+	for _, line := range lines {
+		var b strings.Builder
+		for j, val := range line {
+			val = Truncate(val, widths[j])
+			b.WriteString(val)
+			if j < len(line)-1 {
+				b.WriteString(strings.Repeat(" ", widths[j]-Width(val)+2))
+			}
+		}
+		if _, err := fmt.Fprintln(w, strings.TrimRight(b.String(), " ")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// columns returns the indexes of the columns to show.
+func (t *Table) columns(wide bool) ([]int, error) {
+	if len(Columns) == 0 {
+		n := len(t.Headers)
+		if !wide && t.Wide > 0 && t.Wide < n {
+			n -= t.Wide
+		}
+		cols := make([]int, n)
+		for i := range cols {
+			cols[i] = i
+		}
+		return cols, nil
+	}
+
+	var cols []int
+	for _, name := range Columns {
+		c, err := t.column(name)
+		if err != nil {
+			return nil, err
+		}
+		cols = append(cols, c)
+	}
+	return cols, nil
+}
+
+// column finds a column by header name, ignoring case and treating spaces,
+// dashes and underscores alike.
+func (t *Table) column(name string) (int, error) {
+	key := columnKey(name)
+	names := make([]string, len(t.Headers))
+	for i, h := range t.Headers {
+		if columnKey(h) == key {
+			return i, nil
+		}
+		names[i] = columnKey(h)
+	}
+	return 0, &ColumnError{Name: name, Columns: names}
+}
+
+// ColumnError is a --columns or --sort-by column the table does not have.
+type ColumnError struct {
+	Name    string
+	Columns []string
+}
+
+func (e *ColumnError) Error() string {
+	return fmt.Sprintf("unknown column %q (columns: %s)", e.Name, strings.Join(e.Columns, ", "))
+}
+
+func columnKey(name string) string {
+	return strings.NewReplacer(" ", "_", "-", "_").Replace(strings.ToLower(strings.TrimSpace(name)))
+}
+
+// sorted returns the rows ordered by SortBy.
+func (t *Table) sorted() ([][]string, error) {
+	if SortBy == "" {
+		return t.Rows, nil
+	}
+	name, desc := strings.TrimPrefix(SortBy, "-"), strings.HasPrefix(SortBy, "-")
+	c, err := t.column(name)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := append([][]string(nil), t.Rows...)
+	at := func(row []string) string {
+		if c < len(row) {
+			return row[c]
+		}
+		return ""
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		a, b := at(rows[i]), at(rows[j])
+		if desc {
+			a, b = b, a
+		}
+		return lessCell(a, b)
+	})
+	return rows, nil
+}
+
+// lessCell compares numbers numerically, relative times chronologically and
+// anything else as text.
+func lessCell(a, b string) bool {
+	a, b = stripANSI(a), stripANSI(b)
+	if x, ok := parseRelative(a); ok {
+		if y, ok := parseRelative(b); ok {
+			return x < y
+		}
+	}
+	x, errA := strconv.ParseFloat(a, 64)
+	y, errB := strconv.ParseFloat(b, 64)
+	if errA == nil && errB == nil {
+		return x < y
+	}
+	return strings.ToLower(a) < strings.ToLower(b)
+}
+
+// fit narrows the flexible columns, widest first, until the table fits in
+// width. A width of 0 means no limit.
+func (t *Table) fit(cols, widths []int, width int) {
+	if width <= 0 {
+		return
+	}
+	flexible := make([]bool, len(cols))
+	for j, c := range cols {
+		if len(t.Flexible) == 0 {
+			flexible[j] = c > 0
+			continue
+		}
+		for _, name := range t.Flexible {
+			if columnKey(name) == columnKey(t.Headers[c]) {
+				flexible[j] = true
+			}
+		}
+	}
+	min := make([]int, len(cols))
+	for j, c := range cols {
+		min[j] = minFlexWidth
+		if hw := Width(t.Headers[c]); hw > min[j] {
+			min[j] = hw
+		}
+	}
+
+	total := 2 * (len(cols) - 1)
+	for _, w := range widths {
+		total += w
+	}
+	for total > width {
+		widest := -1
+		for j, w := range widths {
+			if flexible[j] && w > min[j] && (widest < 0 || w > widths[widest]) {
+				widest = j
+			}
+		}
+		if widest < 0 {
+			return
+		}
+		widths[widest]--
+		total--
+	}
+}
+
+// Width is the number of terminal cells s takes up: wide East Asian
+// characters and most emoji take two, combining marks and colors none.
+func Width(s string) int {
+	return runewidth.StringWidth(stripANSI(s))
+}
+
+// Truncate shortens s to at most width terminal cells, ending it with "..."
+// when there is room for it. It never splits a character. Truncated text
+// loses its color.
+func Truncate(s string, width int) string {
+	if Width(s) <= width {
+		return s
+	}
+	s = stripANSI(s)
+	if width <= 0 {
+		return ""
+	}
+	tail := "..."
+	if width < len(tail) {
+		tail = ""
+	}
+	return runewidth.Truncate(s, width, tail)
+}
+
+// TerminalWidth returns the width of the terminal stdout is writing to,
+// $COLUMNS when stdout is not a terminal, or 0 if neither is known.
+func TerminalWidth() int {
+	if fd := int(os.Stdout.Fd()); term.IsTerminal(fd) {
+		if w, _, err := term.GetSize(fd); err == nil && w > 0 {
+			return w
+		}
+	}
+	if w, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && w > 0 {
+		return w
+	}
+	return 0
+}
+
+// tableOf lays out a value without a table view: a list of objects as one
+// row per object, a single object as FIELD/VALUE rows.
+func tableOf(v interface{}) (*Table, error) {
+	data, err := decodeOrdered(v)
+	if err != nil {
+		return nil, err
+	}
+	if obj, ok := data.(*object); ok {
+		t := &Table{Headers: []string{"FIELD", "VALUE"}}
+		for _, k := range obj.keys {
+			t.Rows = append(t.Rows, []string{k, cell(obj.values[k])})
+		}
+		return t, nil
+	}
+	headers, rows := records(data)
+	for i, h := range headers {
+		headers[i] = strings.ToUpper(h)
+	}
+	return &Table{Headers: headers, Rows: rows}, nil
+}
@@ -0,0 +1,126 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		width    int
+		expected string
+	}{
+		{"ShortString", "hello", 10, "hello"},
+		{"ExactLength", "hello", 5, "hello"},
+		{"LongString", "hello world, this is a long string", 10, "hello w..."},
+		{"EmptyString", "", 10, ""},
+		{"ZeroWidth", "hello", 0, ""},
+		{"NoRoomForEllipsis", "hello", 2, "he"},
+		{"MultiByte", "héllo wörld", 8, "héllo..."},
+		{"Wide", "日本語のテキスト", 9, "日本語..."},
+		{"Emoji", "🚀🚀🚀🚀🚀", 7, "🚀🚀..."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Truncate(tt.input, tt.width)
+			if got != tt.expected {
+				t.Errorf("Truncate(%q, %d) = %q, want %q", tt.input, tt.width, got, tt.expected)
+			}
+			if Width(got) > tt.width {
+				t.Errorf("Truncate(%q, %d) is %d cells wide", tt.input, tt.width, Width(got))
+			}
+		})
+	}
+}
+
+func renderTable(t *testing.T, table *Table, wide bool) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := (tableFormatter{wide: wide}).Format(&buf, table); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return buf.String()
+}
+
+func resetTableOptions() {
+	Columns, SortBy, NoHeaders = nil, "", false
+}
+
+var testTable = &Table{
+	Headers: []string{"ID", "TITLE", "PRIORITY", "DUE DATE"},
+	Rows: [][]string{
+		{"t1", "Write docs", "2", "2024-03-01"},
+		{"t2", "Ship 🚀", "10", ""},
+		{"t3", "Review", "1", "2024-01-15"},
+	},
+	Wide: 1,
+}
+
+func TestTableFormatter_Options(t *testing.T) {
+	t.Setenv("COLUMNS", "")
+	defer resetTableOptions()
+
+	tests := []struct {
+		name      string
+		columns   []string
+		sortBy    string
+		noHeaders bool
+		want      string
+	}{
+		{"Default", nil, "", false, "ID  TITLE       PRIORITY\nt1  Write docs  2\nt2  Ship 🚀     10\nt3  Review      1\n"},
+		{"Columns", []string{"due_date", "id"}, "", false, "DUE DATE    ID\n2024-03-01  t1\n            t2\n2024-01-15  t3\n"},
+		{"SortNumeric", []string{"id"}, "priority", false, "ID\nt3\nt1\nt2\n"},
+		{"SortDescending", []string{"id"}, "-title", false, "ID\nt1\nt2\nt3\n"},
+		{"NoHeaders", []string{"id", "priority"}, "", true, "t1  2\nt2  10\nt3  1\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			Columns, SortBy, NoHeaders = tt.columns, tt.sortBy, tt.noHeaders
+			if got := renderTable(t, testTable, false); got != tt.want {
+				t.Errorf("expected:\n%s\ngot:\n%s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestTableFormatter_UnknownColumn(t *testing.T) {
+	defer resetTableOptions()
+
+	Columns = []string{"owner"}
+	err := (tableFormatter{}).Format(&bytes.Buffer{}, testTable)
+	if err == nil || !strings.Contains(err.Error(), "due_date") {
+		t.Errorf("expected unknown column error listing columns, got %v", err)
+	}
+
+	Columns, SortBy = nil, "owner"
+	if err := (tableFormatter{}).Format(&bytes.Buffer{}, testTable); err == nil {
+		t.Error("expected error sorting by an unknown column")
+	}
+}
+
+func TestTableFormatter_FitsTerminal(t *testing.T) {
+	table := &Table{
+		Headers: []string{"ID", "TITLE", "STATUS"},
+		Rows:    [][]string{{"3f2a9c1e", "Investigate the flaky deploy pipeline on staging", "in_progress"}},
+	}
+
+	t.Setenv("COLUMNS", "40")
+	got := renderTable(t, table, false)
+	for _, line := range strings.Split(strings.TrimRight(got, "\n"), "\n") {
+		if Width(line) > 40 {
+			t.Errorf("expected lines to fit in 40 columns, got %d: %q", Width(line), line)
+		}
+	}
+	if !strings.Contains(got, "3f2a9c1e  Investigate th...  in_progress") {
+		t.Errorf("expected the title truncated and the ID kept, got:\n%s", got)
+	}
+
+	if got := renderTable(t, table, true); !strings.Contains(got, "staging") {
+		t.Errorf("expected the wide format not to truncate, got:\n%s", got)
+	}
+}
@@ -0,0 +1,99 @@
+package realtime
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Gahroot/agentHQ-cli/pkg/agenthq"
+)
+
+// Events sent by the hub.
+const (
+	EventNotificationNew = "notification:new"
+	EventTaskNew         = "task:new"
+	EventTaskUpdated     = "task:updated"
+	EventSubscribed      = "subscribed"
+	EventUnsubscribed    = "unsubscribed"
+	EventHeartbeatAck    = "heartbeat_ack"
+)
+
+// Events sent by the client.
+const (
+	eventSubscribe   = "subscribe"
+	eventUnsubscribe = "unsubscribe"
+	eventHeartbeat   = "heartbeat"
+)
+
+// Event is a single message received from the hub. Data holds the raw
+// payload; use the typed accessors to decode it.
+type Event struct {
+	Name       string          `json:"event"`
+	Data       json.RawMessage `json:"data,omitempty"`
+	ReceivedAt time.Time       `json:"-"`
+}
+
+// NotificationEvent is the payload of notification:new.
+type NotificationEvent struct {
+	RecipientID  string               `json:"recipientId"`
+	Notification agenthq.Notification `json:"notification"`
+}
+
+// TaskEvent is the payload of task:new and task:updated.
+type TaskEvent struct {
+	Task agenthq.Task `json:"task"`
+}
+
+// SubscriptionEvent is the payload of subscribed and unsubscribed.
+type SubscriptionEvent struct {
+	Channel string `json:"channel"`
+}
+
+// HeartbeatAck is the payload of heartbeat_ack.
+type HeartbeatAck struct {
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Notification decodes a notification:new payload.
+func (e Event) Notification() (*NotificationEvent, error) {
+	var v NotificationEvent
+	if err := e.decode(&v, EventNotificationNew); err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// Task decodes a task:new or task:updated payload.
+func (e Event) Task() (*TaskEvent, error) {
+	var v TaskEvent
+	if err := e.decode(&v, EventTaskNew, EventTaskUpdated); err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// Subscription decodes a subscribed or unsubscribed payload.
+func (e Event) Subscription() (*SubscriptionEvent, error) {
+	var v SubscriptionEvent
+	if err := e.decode(&v, EventSubscribed, EventUnsubscribed); err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+func (e Event) decode(v interface{}, names ...string) error {
+	for _, name := range names {
+		if e.Name == name {
+			if err := json.Unmarshal(e.Data, v); err != nil {
+				return fmt.Errorf("failed to parse %s event: %w", e.Name, err)
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("event %q is not %v", e.Name, names)
+}
+
+type outgoing struct {
+	Event string      `json:"event"`
+	Data  interface{} `json:"data,omitempty"`
+}
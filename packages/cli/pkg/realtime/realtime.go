@@ -0,0 +1,372 @@
+// Package realtime streams live events from the AgentHQ hub's /ws endpoint.
+//
+//	rt, err := realtime.Connect(ctx, hubURL, token, realtime.Options{
+//		Channels: []string{"general"},
+//	})
+//	if err != nil {
+//		return err
+//	}
+//	defer rt.Close()
+//	for ev := range rt.Events() {
+//		if ev.Name == realtime.EventTaskNew {
+//			t, _ := ev.Task()
+//			fmt.Println(t.Task.Title)
+//		}
+//	}
+//
+// The connection is re-established with exponential backoff when it drops,
+// and channel subscriptions are replayed after every reconnect.
+package realtime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Gahroot/agentHQ-cli/internal/common/config"
+	"github.com/gorilla/websocket"
+)
+
+// closeAuthFailed is the close code the hub uses for a missing or invalid
+// token.
+const closeAuthFailed = 4001
+
+// ErrAuth is returned when the hub rejects the token. The client does not
+// reconnect after it.
+var ErrAuth = errors.New("websocket authentication failed")
+
+// Options tunes a Client. Zero values use the defaults noted on each field.
+type Options struct {
+	// Channels to subscribe to once connected.
+	Channels []string
+	// HeartbeatInterval between heartbeat messages (default 25s).
+	HeartbeatInterval time.Duration
+	// HeartbeatTimeout after which a connection without a heartbeat_ack is
+	// considered dead and re-established (default 2 × HeartbeatInterval).
+	HeartbeatTimeout time.Duration
+	// ReconnectMin and ReconnectMax bound the reconnect backoff
+	// (default 1s and 30s).
+	ReconnectMin time.Duration
+	ReconnectMax time.Duration
+	// OnConnect, if set, is called after every successful (re)connect.
+	OnConnect func()
+	// OnDisconnect, if set, is called with the error that dropped the
+	// connection before a reconnect is attempted.
+	OnDisconnect func(err error)
+}
+
+func (o *Options) setDefaults() {
+	if o.HeartbeatInterval <= 0 {
+		o.HeartbeatInterval = 25 * time.Second
+	}
+	if o.HeartbeatTimeout <= 0 {
+		o.HeartbeatTimeout = 2 * o.HeartbeatInterval
+	}
+	if o.ReconnectMin <= 0 {
+		o.ReconnectMin = time.Second
+	}
+	if o.ReconnectMax <= 0 {
+		o.ReconnectMax = 30 * time.Second
+	}
+}
+
+// Client is a live connection to the hub. Its methods are safe for
+// concurrent use.
+type Client struct {
+	url    string
+	opts   Options
+	events chan Event
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	mu       sync.Mutex
+	conn     *websocket.Conn
+	channels map[string]bool
+	lastAck  time.Time
+	err      error
+
+	writeMu sync.Mutex
+}
+
+// ConnectWithConfig connects using the hub URL and credentials from the
+// user's agenthq config file.
+func ConnectWithConfig(ctx context.Context, opts Options) (*Client, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	if err := cfg.LoadCredentials(); err != nil {
+		return nil, err
+	}
+	return Connect(ctx, cfg.HubURL, cfg.GetAuthToken(), opts)
+}
+
+// Connect opens a connection to hubURL authenticating with token, which
+// may be an agent API key or a user JWT. The first connection attempt is
+// made synchronously so a bad URL or token is reported here; later drops
+// are retried in the background until ctx is done or Close is called.
+func Connect(ctx context.Context, hubURL, token string, opts Options) (*Client, error) {
+	if token == "" {
+		return nil, fmt.Errorf("%w: no token configured", ErrAuth)
+	}
+	wsURL, err := websocketURL(hubURL, token)
+	if err != nil {
+		return nil, err
+	}
+	opts.setDefaults()
+
+	ctx, cancel := context.WithCancel(ctx)
+	c := &Client{
+		url:      wsURL,
+		opts:     opts,
+		events:   make(chan Event, 64),
+		cancel:   cancel,
+		done:     make(chan struct{}),
+		channels: map[string]bool{},
+	}
+	for _, ch := range opts.Channels {
+		c.channels[ch] = true
+	}
+
+	conn, err := c.dial(ctx)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	go c.run(ctx, conn)
+	return c, nil
+}
+
+// Events returns the stream of hub events. It is closed when the client
+// stops; Err then reports why.
+func (c *Client) Events() <-chan Event {
+	return c.events
+}
+
+// Err returns the error that stopped the client, or nil if it was closed
+// normally.
+func (c *Client) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err
+}
+
+// LastHeartbeat returns when the hub last acknowledged a heartbeat.
+func (c *Client) LastHeartbeat() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lastAck
+}
+
+// Subscribe adds channel to the subscriptions kept across reconnects.
+func (c *Client) Subscribe(channel string) error {
+	c.mu.Lock()
+	c.channels[channel] = true
+	c.mu.Unlock()
+	return c.send(outgoing{Event: eventSubscribe, Data: SubscriptionEvent{Channel: channel}})
+}
+
+// Unsubscribe removes channel from the subscriptions.
+func (c *Client) Unsubscribe(channel string) error {
+	c.mu.Lock()
+	delete(c.channels, channel)
+	c.mu.Unlock()
+	return c.send(outgoing{Event: eventUnsubscribe, Data: SubscriptionEvent{Channel: channel}})
+}
+
+// Close shuts the connection down and waits for the event stream to close.
+func (c *Client) Close() error {
+	c.cancel()
+	<-c.done
+	return nil
+}
+
+func (c *Client) dial(ctx context.Context) (*websocket.Conn, error) {
+	conn, resp, err := websocket.DefaultDialer.DialContext(ctx, c.url, nil)
+	if err != nil {
+		if resp != nil {
+			return nil, fmt.Errorf("websocket handshake failed with status %d", resp.StatusCode)
+		}
+		return nil, fmt.Errorf("websocket connect failed: %w", err)
+	}
+
+	c.mu.Lock()
+	c.conn = conn
+	c.lastAck = time.Now()
+	channels := make([]string, 0, len(c.channels))
+	for ch := range c.channels {
+		channels = append(channels, ch)
+	}
+	c.mu.Unlock()
+
+	for _, ch := range channels {
+		if err := c.send(outgoing{Event: eventSubscribe, Data: SubscriptionEvent{Channel: ch}}); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	if c.opts.OnConnect != nil {
+		c.opts.OnConnect()
+	}
+	return conn, nil
+}
+
+// run reads from conn and reconnects whenever it drops.
+func (c *Client) run(ctx context.Context, conn *websocket.Conn) {
+	defer close(c.done)
+	defer close(c.events)
+
+	for attempt := 0; ; {
+		err := c.serve(ctx, conn)
+		if ctx.Err() != nil {
+			return
+		}
+		if errors.Is(err, ErrAuth) {
+			c.fail(err)
+			return
+		}
+		if c.opts.OnDisconnect != nil {
+			c.opts.OnDisconnect(err)
+		}
+
+		for {
+			attempt++
+			if !sleep(ctx, c.backoff(attempt)) {
+				return
+			}
+			conn, err = c.dial(ctx)
+			if err == nil {
+				attempt = 0
+				break
+			}
+			if c.opts.OnDisconnect != nil {
+				c.opts.OnDisconnect(err)
+			}
+		}
+	}
+}
+
+// serve pumps one connection until it fails or ctx is done.
+func (c *Client) serve(ctx context.Context, conn *websocket.Conn) error {
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		select {
+		case <-ctx.Done():
+			c.writeMu.Lock()
+			conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+			c.writeMu.Unlock()
+			conn.Close()
+		case <-stop:
+			conn.Close()
+		}
+	}()
+	go c.heartbeat(conn, stop)
+
+	for {
+		var ev Event
+		if err := conn.ReadJSON(&ev); err != nil {
+			var closeErr *websocket.CloseError
+			if errors.As(err, &closeErr) && closeErr.Code == closeAuthFailed {
+				return fmt.Errorf("%w: %s", ErrAuth, closeErr.Text)
+			}
+			return err
+		}
+		ev.ReceivedAt = time.Now()
+		if ev.Name == EventHeartbeatAck {
+			c.mu.Lock()
+			c.lastAck = ev.ReceivedAt
+			c.mu.Unlock()
+		}
+		select {
+		case c.events <- ev:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// heartbeat sends heartbeats on conn and closes it when the hub stops
+// acknowledging them.
+func (c *Client) heartbeat(conn *websocket.Conn, stop <-chan struct{}) {
+	ticker := time.NewTicker(c.opts.HeartbeatInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if time.Since(c.LastHeartbeat()) > c.opts.HeartbeatTimeout {
+				conn.Close()
+				return
+			}
+			if err := c.send(outgoing{Event: eventHeartbeat}); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (c *Client) send(msg outgoing) error {
+	c.mu.Lock()
+	conn := c.conn
+	c.mu.Unlock()
+	if conn == nil {
+		return fmt.Errorf("websocket not connected")
+	}
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+	return conn.WriteJSON(msg)
+}
+
+func (c *Client) fail(err error) {
+	c.mu.Lock()
+	c.err = err
+	c.mu.Unlock()
+}
+
+// backoff returns a jittered exponential delay for the given attempt.
+func (c *Client) backoff(attempt int) time.Duration {
+	d := c.opts.ReconnectMin << uint(attempt-1)
+	if d <= 0 || d > c.opts.ReconnectMax {
+		d = c.opts.ReconnectMax
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+func sleep(ctx context.Context, d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-t.C:
+		return true
+	}
+}
+
+// websocketURL turns the hub's HTTP base URL into its /ws endpoint.
+func websocketURL(hubURL, token string) (string, error) {
+	u, err := url.Parse(strings.TrimRight(hubURL, "/"))
+	if err != nil {
+		return "", fmt.Errorf("invalid hub URL: %w", err)
+	}
+	switch u.Scheme {
+	case "http", "ws":
+		u.Scheme = "ws"
+	case "https", "wss":
+		u.Scheme = "wss"
+	default:
+		return "", fmt.Errorf("invalid hub URL %q: expected http or https", hubURL)
+	}
+	u.Path += "/ws"
+	u.RawQuery = url.Values{"token": {token}}.Encode()
+	return u.String(), nil
+}
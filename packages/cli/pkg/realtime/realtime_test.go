@@ -0,0 +1,171 @@
+package realtime
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+var upgrader = websocket.Upgrader{}
+
+// fakeHub answers subscribe and heartbeat like the hub does and sends a
+// task:new after each subscription.
+func fakeHub(t *testing.T, onConn func(n int32, conn *websocket.Conn) bool) *httptest.Server {
+	var conns int32
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/ws" || r.URL.Query().Get("token") != "ahq_test" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("upgrade failed: %v", err)
+			return
+		}
+		defer conn.Close()
+		n := atomic.AddInt32(&conns, 1)
+		if onConn != nil && !onConn(n, conn) {
+			return
+		}
+
+		for {
+			var msg struct {
+				Event string            `json:"event"`
+				Data  map[string]string `json:"data"`
+			}
+			if err := conn.ReadJSON(&msg); err != nil {
+				return
+			}
+			switch msg.Event {
+			case "subscribe":
+				conn.WriteJSON(map[string]interface{}{"event": "subscribed", "data": msg.Data})
+				conn.WriteJSON(map[string]interface{}{"event": "task:new", "data": map[string]interface{}{"task": map[string]string{"id": "t1", "title": "Ship it"}}})
+			case "heartbeat":
+				conn.WriteJSON(map[string]interface{}{"event": "heartbeat_ack", "data": map[string]string{"timestamp": time.Now().UTC().Format(time.RFC3339Nano)}})
+			}
+		}
+	}))
+}
+
+func nextEvent(t *testing.T, rt *Client, name string) Event {
+	t.Helper()
+	timeout := time.After(2 * time.Second)
+	for {
+		select {
+		case ev, ok := <-rt.Events():
+			if !ok {
+				t.Fatalf("event stream closed waiting for %s: %v", name, rt.Err())
+			}
+			if ev.Name == name {
+				return ev
+			}
+		case <-timeout:
+			t.Fatalf("timed out waiting for %s", name)
+		}
+	}
+}
+
+func TestConnect_SubscribeAndTypedEvents(t *testing.T) {
+	server := fakeHub(t, nil)
+	defer server.Close()
+
+	rt, err := Connect(context.Background(), server.URL, "ahq_test", Options{Channels: []string{"general"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer rt.Close()
+
+	sub, err := nextEvent(t, rt, EventSubscribed).Subscription()
+	if err != nil || sub.Channel != "general" {
+		t.Errorf("expected subscribed to general, got %+v (err=%v)", sub, err)
+	}
+	task, err := nextEvent(t, rt, EventTaskNew).Task()
+	if err != nil || task.Task.Title != "Ship it" {
+		t.Errorf("expected task 'Ship it', got %+v (err=%v)", task, err)
+	}
+}
+
+func TestConnect_ReconnectResubscribes(t *testing.T) {
+	server := fakeHub(t, func(n int32, conn *websocket.Conn) bool {
+		// Drop the first connection straight away.
+		return n > 1
+	})
+	defer server.Close()
+
+	var disconnects int32
+	rt, err := Connect(context.Background(), server.URL, "ahq_test", Options{
+		Channels:     []string{"general"},
+		ReconnectMin: time.Millisecond,
+		ReconnectMax: 5 * time.Millisecond,
+		OnDisconnect: func(error) { atomic.AddInt32(&disconnects, 1) },
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer rt.Close()
+
+	nextEvent(t, rt, EventSubscribed)
+	if atomic.LoadInt32(&disconnects) == 0 {
+		t.Error("expected a disconnect before resubscribing")
+	}
+}
+
+func TestConnect_Heartbeat(t *testing.T) {
+	server := fakeHub(t, nil)
+	defer server.Close()
+
+	rt, err := Connect(context.Background(), server.URL, "ahq_test", Options{HeartbeatInterval: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer rt.Close()
+
+	before := rt.LastHeartbeat()
+	ev := nextEvent(t, rt, EventHeartbeatAck)
+	if !rt.LastHeartbeat().After(before) || rt.LastHeartbeat().Before(ev.ReceivedAt) {
+		t.Errorf("expected LastHeartbeat to advance to the ack, got %v", rt.LastHeartbeat())
+	}
+}
+
+func TestConnect_AuthFailureStops(t *testing.T) {
+	server := fakeHub(t, func(n int32, conn *websocket.Conn) bool {
+		conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(closeAuthFailed, "Invalid authentication"))
+		return false
+	})
+	defer server.Close()
+
+	rt, err := Connect(context.Background(), server.URL, "ahq_test", Options{ReconnectMin: time.Millisecond})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for range rt.Events() {
+	}
+	if !errors.Is(rt.Err(), ErrAuth) {
+		t.Errorf("expected ErrAuth, got %v", rt.Err())
+	}
+}
+
+func TestWebsocketURL(t *testing.T) {
+	tests := []struct {
+		hub  string
+		want string
+	}{
+		{"http://localhost:3000", "ws://localhost:3000/ws?token=tok"},
+		{"https://hub.example.com/", "wss://hub.example.com/ws?token=tok"},
+		{"https://example.com/agenthq", "wss://example.com/agenthq/ws?token=tok"},
+	}
+	for _, tt := range tests {
+		got, err := websocketURL(tt.hub, "tok")
+		if err != nil || got != tt.want {
+			t.Errorf("websocketURL(%q) = %q, %v; want %q", tt.hub, got, err, tt.want)
+		}
+	}
+	if _, err := websocketURL("ftp://x", "tok"); err == nil {
+		t.Error("expected error for unsupported scheme")
+	}
+}
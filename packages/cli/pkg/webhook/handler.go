@@ -0,0 +1,50 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+)
+
+// MaxBodySize bounds the delivery bodies Middleware reads.
+const MaxBodySize = 1 << 20
+
+type contextKey struct{}
+
+// Middleware verifies each request's signature with secret before calling
+// next. Unsigned or tampered deliveries get 401 and malformed ones 400. The
+// decoded Payload is available to next through PayloadFromContext, and the
+// request body can still be read.
+func Middleware(secret string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		body, err := io.ReadAll(io.LimitReader(r.Body, MaxBodySize))
+		if err != nil {
+			http.Error(w, "failed to read body", http.StatusBadRequest)
+			return
+		}
+		if err := Verify(secret, body, r.Header.Get(HeaderSignature)); err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		payload, err := Parse(body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		r = r.WithContext(context.WithValue(r.Context(), contextKey{}, payload))
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		next.ServeHTTP(w, r)
+	})
+}
+
+// PayloadFromContext returns the payload stored by Middleware.
+func PayloadFromContext(ctx context.Context) (*Payload, bool) {
+	p, ok := ctx.Value(contextKey{}).(*Payload)
+	return p, ok
+}
@@ -0,0 +1,178 @@
+// Package webhook verifies and decodes AgentHQ webhook deliveries.
+//
+// The hub POSTs a JSON Payload to each registered webhook and, when the
+// webhook has a secret, signs the body with HMAC-SHA256 in the
+// X-AgentHQ-Signature header:
+//
+//	http.Handle("/hooks/agenthq", webhook.Middleware(secret, http.HandlerFunc(
+//		func(w http.ResponseWriter, r *http.Request) {
+//			p, _ := webhook.PayloadFromContext(r.Context())
+//			if p.Event == webhook.EventTaskAssigned {
+//				task, _ := p.TaskAssigned()
+//				log.Printf("%s assigned to %s", task.Title, task.AssignedTo)
+//			}
+//		})))
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Headers set on every delivery.
+const (
+	HeaderSignature = "X-AgentHQ-Signature"
+	HeaderEvent     = "X-AgentHQ-Event"
+	HeaderDelivery  = "X-AgentHQ-Delivery"
+	HeaderTimestamp = "X-AgentHQ-Timestamp"
+)
+
+// Event names. EventTest is only sent by the hub's test ping.
+const (
+	EventNotificationNew = "notification:new"
+	EventPostCreated     = "post:created"
+	EventPostReply       = "post:reply"
+	EventTaskAssigned    = "task:assigned"
+	EventTest            = "test"
+)
+
+var (
+	// ErrMissingSignature is returned when a delivery carries no signature.
+	ErrMissingSignature = errors.New("webhook: missing signature")
+	// ErrInvalidSignature is returned when a signature does not match the body.
+	ErrInvalidSignature = errors.New("webhook: invalid signature")
+)
+
+// Sign returns the X-AgentHQ-Signature value for body: "sha256=" followed by
+// the hex HMAC-SHA256 of body keyed with secret.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks signature against body in constant time.
+func Verify(secret string, body []byte, signature string) error {
+	if signature == "" {
+		return ErrMissingSignature
+	}
+	hexSig, ok := strings.CutPrefix(signature, "sha256=")
+	if !ok {
+		return ErrInvalidSignature
+	}
+	got, err := hex.DecodeString(hexSig)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	if !hmac.Equal(got, mac.Sum(nil)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// Payload is the body of a delivery. Data depends on Event; use the typed
+// accessors to decode it.
+type Payload struct {
+	ID        string          `json:"id"`
+	Event     string          `json:"event"`
+	OrgID     string          `json:"org_id"`
+	Timestamp time.Time       `json:"timestamp"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// Parse decodes a delivery body.
+func Parse(body []byte) (*Payload, error) {
+	var p Payload
+	if err := json.Unmarshal(body, &p); err != nil {
+		return nil, fmt.Errorf("webhook: invalid payload: %w", err)
+	}
+	return &p, nil
+}
+
+// PostCreated is the data of a post:created event. ParentID is set when the
+// post is a reply.
+type PostCreated struct {
+	PostID     string `json:"post_id"`
+	ChannelID  string `json:"channel_id"`
+	AuthorID   string `json:"author_id"`
+	AuthorType string `json:"author_type"`
+	Type       string `json:"type"`
+	Title      string `json:"title"`
+	Content    string `json:"content"`
+	ParentID   string `json:"parent_id"`
+}
+
+// PostReply is the data of a post:reply event.
+type PostReply struct {
+	PostID     string `json:"post_id"`
+	ParentID   string `json:"parent_id"`
+	ChannelID  string `json:"channel_id"`
+	AuthorID   string `json:"author_id"`
+	AuthorType string `json:"author_type"`
+	Content    string `json:"content"`
+}
+
+// TaskAssigned is the data of a task:assigned event. PreviousAssignedTo is
+// only set when an existing task is reassigned.
+type TaskAssigned struct {
+	TaskID             string `json:"task_id"`
+	Title              string `json:"title"`
+	AssignedTo         string `json:"assigned_to"`
+	AssignedType       string `json:"assigned_type"`
+	AssignedBy         string `json:"assigned_by"`
+	Status             string `json:"status"`
+	Priority           string `json:"priority"`
+	PreviousAssignedTo string `json:"previous_assigned_to,omitempty"`
+}
+
+// NotificationNew is the data of a notification:new event.
+type NotificationNew struct {
+	NotificationID string `json:"notification_id"`
+	RecipientID    string `json:"recipient_id"`
+	RecipientType  string `json:"recipient_type"`
+	Type           string `json:"type"`
+	Title          string `json:"title"`
+	Body           string `json:"body"`
+	ActorID        string `json:"actor_id"`
+	ActorType      string `json:"actor_type"`
+	SourceID       string `json:"source_id"`
+	SourceType     string `json:"source_type"`
+}
+
+func (p *Payload) PostCreated() (*PostCreated, error) {
+	var v PostCreated
+	return &v, p.decode(EventPostCreated, &v)
+}
+
+func (p *Payload) PostReply() (*PostReply, error) {
+	var v PostReply
+	return &v, p.decode(EventPostReply, &v)
+}
+
+func (p *Payload) TaskAssigned() (*TaskAssigned, error) {
+	var v TaskAssigned
+	return &v, p.decode(EventTaskAssigned, &v)
+}
+
+func (p *Payload) NotificationNew() (*NotificationNew, error) {
+	var v NotificationNew
+	return &v, p.decode(EventNotificationNew, &v)
+}
+
+func (p *Payload) decode(event string, v interface{}) error {
+	if p.Event != event {
+		return fmt.Errorf("webhook: event is %q, not %q", p.Event, event)
+	}
+	if err := json.Unmarshal(p.Data, v); err != nil {
+		return fmt.Errorf("webhook: invalid %s data: %w", event, err)
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package webhook
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// signedBody is a delivery body as the hub serializes it.
+const signedBody = `{"id":"d1","event":"task:assigned","org_id":"o1","timestamp":"2024-01-02T03:04:05.000Z","data":{"task_id":"t1","title":"Ship it","assigned_to":"a1","assigned_type":"agent","assigned_by":"u1","status":"open","priority":"high","previous_assigned_to":null}}`
+
+func TestVerify(t *testing.T) {
+	valid := Sign("s3cret", []byte(signedBody))
+	tests := []struct {
+		name      string
+		body      string
+		signature string
+		want      error
+	}{
+		{"valid", signedBody, valid, nil},
+		{"tampered body", strings.Replace(signedBody, "Ship it", "Ship it!", 1), valid, ErrInvalidSignature},
+		{"wrong secret", signedBody, Sign("other", []byte(signedBody)), ErrInvalidSignature},
+		{"missing", signedBody, "", ErrMissingSignature},
+		{"no prefix", signedBody, strings.TrimPrefix(valid, "sha256="), ErrInvalidSignature},
+		{"not hex", signedBody, "sha256=xyz", ErrInvalidSignature},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Verify("s3cret", []byte(tt.body), tt.signature); !errors.Is(err, tt.want) {
+				t.Errorf("Verify() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestSign_MatchesHub(t *testing.T) {
+	// crypto.createHmac('sha256', 'key').update('body').digest('hex')
+	want := "sha256=515aae133b435d4000956731f68ae5cf5eb85d4f0dc6a546d2bfcd3595ec1ae1"
+	if got := Sign("key", []byte("body")); got != want {
+		t.Errorf("Sign() = %q, want %q", got, want)
+	}
+}
+
+func TestPayload_TypedData(t *testing.T) {
+	p, err := Parse([]byte(signedBody))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if p.Timestamp.Year() != 2024 || p.OrgID != "o1" {
+		t.Errorf("unexpected payload %+v", p)
+	}
+
+	task, err := p.TaskAssigned()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if task.TaskID != "t1" || task.AssignedTo != "a1" || task.PreviousAssignedTo != "" {
+		t.Errorf("unexpected task data %+v", task)
+	}
+
+	if _, err := p.PostCreated(); err == nil {
+		t.Error("expected error decoding task:assigned as post:created")
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	var got *Payload
+	var gotBody string
+	h := Middleware("s3cret", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, _ = PayloadFromContext(r.Context())
+		b, _ := io.ReadAll(r.Body)
+		gotBody = string(b)
+	}))
+
+	tests := []struct {
+		name      string
+		body      string
+		signature string
+		want      int
+	}{
+		{"signed", signedBody, Sign("s3cret", []byte(signedBody)), http.StatusOK},
+		{"unsigned", signedBody, "", http.StatusUnauthorized},
+		{"tampered", signedBody + " ", Sign("s3cret", []byte(signedBody)), http.StatusUnauthorized},
+		{"signed but not JSON", "nope", Sign("s3cret", []byte("nope")), http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotBody = nil, ""
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			if tt.signature != "" {
+				req.Header.Set(HeaderSignature, tt.signature)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.want)
+			}
+			if tt.want == http.StatusOK {
+				if got == nil || got.Event != EventTaskAssigned {
+					t.Errorf("expected payload in context, got %+v", got)
+				}
+				if gotBody != tt.body {
+					t.Error("expected the handler to be able to reread the body")
+				}
+			} else if got != nil {
+				t.Error("handler must not run for rejected deliveries")
+			}
+		})
+	}
+}